@@ -0,0 +1,60 @@
+package address
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Unknown dipakai kalau kota tidak bisa ditentukan dari alamat
+const Unknown = "unknown"
+
+var (
+	postalCode   = regexp.MustCompile(`\b\d{5}\b`)
+	streetPrefix = regexp.MustCompile(`(?i)^(jl|jln|jalan|gg|gang|no|rt|rw|blok|perum|komp)\b`)
+	cityPrefix   = regexp.MustCompile(`(?i)^(kota|kab\.?|kabupaten|kec\.?|kecamatan)\s+`)
+)
+
+// provinsi di Indonesia, segmen alamat yang berisi nama provinsi dilewati
+var provinces = map[string]bool{
+	"aceh": true, "sumatera utara": true, "sumatera barat": true, "riau": true, "kepulauan riau": true,
+	"jambi": true, "sumatera selatan": true, "bangka belitung": true, "kepulauan bangka belitung": true,
+	"bengkulu": true, "lampung": true, "dki jakarta": true, "jawa barat": true, "jawa tengah": true,
+	"di yogyakarta": true, "diy": true, "jawa timur": true, "banten": true, "bali": true,
+	"nusa tenggara barat": true, "ntb": true, "nusa tenggara timur": true, "ntt": true,
+	"kalimantan barat": true, "kalimantan tengah": true, "kalimantan selatan": true,
+	"kalimantan timur": true, "kalimantan utara": true, "sulawesi utara": true, "sulawesi tengah": true,
+	"sulawesi selatan": true, "sulawesi tenggara": true, "sulawesi barat": true, "gorontalo": true,
+	"maluku": true, "maluku utara": true, "papua": true, "papua barat": true, "papua barat daya": true,
+	"papua tengah": true, "papua pegunungan": true, "papua selatan": true, "indonesia": true,
+}
+
+// City menebak nama kota dari alamat bebas dengan mengambil segmen terakhir (dipisah koma) yang bukan
+// kode pos, provinsi atau nama jalan, contoh "Jl. Merdeka No. 1, Kota Bandung, Jawa Barat 40111" menjadi
+// "Bandung". Alamat tanpa koma dianggap nama kota kalau tidak berisi angka dan bukan nama jalan
+func City(addr string) string {
+	segments := strings.Split(addr, ",")
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment := strings.TrimSpace(postalCode.ReplaceAllString(segments[i], ""))
+		segment = strings.Trim(segment, " .-")
+		if segment == "" || provinces[strings.ToLower(segment)] {
+			continue
+		}
+		if streetPrefix.MatchString(segment) || strings.IndexFunc(segment, unicode.IsDigit) >= 0 {
+			break
+		}
+		return title(cityPrefix.ReplaceAllString(segment, ""))
+	}
+	return Unknown
+}
+
+// title huruf pertama setiap kata dibuat kapital supaya "BANDUNG" dan "bandung" dihitung sama
+func title(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
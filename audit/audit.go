@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"encoding/json"
+	"ginDatabaseMhs/model/entity"
+	"reflect"
+)
+
+// Actor pelaku perubahan yang dicatat di history, ID 0 berarti system (contoh purge otomatis)
+type Actor struct {
+	ID        int64
+	RequestID string
+}
+
+// System actor untuk perubahan yang dijalankan aplikasi sendiri
+var System = Actor{}
+
+// Entry satu perubahan entity, Before nil berarti entity baru dibuat dan After nil berarti entity dihapus
+type Entry struct {
+	EntityType string
+	EntityID   int64
+	RecordID   int64
+	Action     string
+	Before     interface{}
+	After      interface{}
+}
+
+// NewLog membuat entry history berisi diff per field antara Before dan After,
+// Version diisi repository saat disimpan
+func NewLog(actor Actor, e Entry) (*entity.AuditLog, error) {
+	beforeFields, err := Fields(e.Before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := Fields(e.After)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]entity.FieldChange{}
+	for key, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[key]) {
+			changes[key] = entity.FieldChange{Before: value, After: afterFields[key]}
+		}
+	}
+	for key, value := range afterFields {
+		if _, ok := beforeFields[key]; !ok && value != nil {
+			changes[key] = entity.FieldChange{Before: nil, After: value}
+		}
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := afterFields
+	if len(afterFields) == 0 {
+		snapshot = beforeFields
+	}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.AuditLog{
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		RecordID:   e.RecordID,
+		Action:     e.Action,
+		ActorID:    actor.ID,
+		RequestID:  actor.RequestID,
+		Changes:    changesJSON,
+		Snapshot:   snapshotJSON,
+	}, nil
+}
+
+// Fields mengubah entity ke map sesuai nama field json,
+// relasi lampiran tidak ikut karena lampiran punya history sendiri
+func Fields(v interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return fields, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	delete(fields, "attachments")
+	return fields, nil
+}
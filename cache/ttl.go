@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// TTL cache sederhana di memory, setiap entry kadaluarsa setelah ttl.
+// ttl <= 0 berarti cache tidak aktif
+type TTL[V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]entry[V]
+}
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+func NewTTL[V any](ttl time.Duration) *TTL[V] {
+	return &TTL[V]{ttl: ttl, entries: map[string]entry[V]{}}
+}
+
+func (c *TTL[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set entry yang sudah kadaluarsa sekalian dibuang supaya map tidak terus membesar
+func (c *TTL[V]) Set(key string, value V) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry[V]{value: value, expires: now.Add(c.ttl)}
+}
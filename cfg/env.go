@@ -1,5 +1,12 @@
 package cfg
 
+import (
+	"errors"
+	"fmt"
+	"github.com/kelseyhightower/envconfig"
+	"time"
+)
+
 type Config struct {
 	DBUsername string `envconfig:"DB_USER"`
 	DBPassword string `envconfig:"DB_PASS"`
@@ -7,10 +14,87 @@ type Config struct {
 	DBPort     int    `envconfig:"DB_PORT"`
 	DBName     string `envconfig:"DB_NAME"`
 
-	//StorageDriver                 string `envconfig:"STORAGE_DRIVER" default:"local"`
-	//StoragePath                   string `envconfig:"STORAGE_PATH"`
-	//LocalStorageDownloadPrefixUrl string `envconfig:"LOCAL_STORAGE_DOWNLOAD_PREFIX_URL"`
-	//
-	//AWSProfile   string `envconfig:"AWS_PROFILE"`
+	// local (folder STORAGE_PATH), s3 atau memory
+	StorageDriver string `envconfig:"STORAGE_DRIVER" default:"local"`
+	StoragePath   string `envconfig:"STORAGE_PATH" default:"uploads"`
+
+	// S3_BUCKET_NAME wajib untuk driver s3, untuk driver local cukup diisi kalau masih ada lampiran lama di S3.
+	// S3_ENDPOINT dan S3_PATH_STYLE diisi untuk layanan kompatibel S3 seperti MinIO
 	S3BucketName string `envconfig:"S3_BUCKET_NAME"`
+	S3Region     string `envconfig:"S3_REGION" default:"us-east-1"`
+	S3Endpoint   string `envconfig:"S3_ENDPOINT"`
+	S3PathStyle  bool   `envconfig:"S3_PATH_STYLE"`
+
+	// kategori file lampiran yang diterima (image, document) dan batas ukurannya dalam byte
+	UploadCategories        []string `envconfig:"UPLOAD_CATEGORIES" default:"image"`
+	UploadMaxImageSize      int64    `envconfig:"UPLOAD_MAX_IMAGE_SIZE" default:"10485760"`
+	UploadMaxDocumentSize   int64    `envconfig:"UPLOAD_MAX_DOCUMENT_SIZE" default:"20971520"`
+	UploadMaxImageDimension int      `envconfig:"UPLOAD_MAX_IMAGE_DIMENSION" default:"8000"`
+	UploadMaxImagePixels    int64    `envconfig:"UPLOAD_MAX_IMAGE_PIXELS" default:"40000000"`
+
+	// ukuran sisi terpanjang variant gambar dalam pixel dan kualitas JPEG hasil encode ulang
+	ImageThumbnailSize int `envconfig:"IMAGE_THUMBNAIL_SIZE" default:"256"`
+	ImageMediumSize    int `envconfig:"IMAGE_MEDIUM_SIZE" default:"1024"`
+	ImageJPEGQuality   int `envconfig:"IMAGE_JPEG_QUALITY" default:"85"`
+
+	// batas lebar x tinggi yang boleh diproses pipeline gambar, lebih ketat dari UPLOAD_MAX_IMAGE_PIXELS
+	// karena gambar di decode ke memori, dan jumlah gambar yang diproses bersamaan
+	ImageMaxPixels      int64 `envconfig:"IMAGE_MAX_PIXELS" default:"24000000"`
+	ImageMaxConcurrency int   `envconfig:"IMAGE_MAX_CONCURRENCY" default:"2"`
+
+	// masa berlaku presigned URL upload dan download (hanya driver s3)
+	PresignExpiry time.Duration `envconfig:"PRESIGN_EXPIRY" default:"15m"`
+	// upload presigned yang kadaluarsa dan tidak pernah diselesaikan dibersihkan setiap interval
+	UploadSweepInterval time.Duration `envconfig:"UPLOAD_SWEEP_INTERVAL" default:"1h"`
+
+	// data yang ada di trash lebih lama dari retention akan dihapus permanen
+	TrashRetention     time.Duration `envconfig:"TRASH_RETENTION" default:"720h"`
+	TrashPurgeInterval time.Duration `envconfig:"TRASH_PURGE_INTERVAL" default:"1h"`
+
+	// mysql (FULLTEXT) atau memory (index di dalam proses)
+	SearchDriver string `envconfig:"SEARCH_DRIVER" default:"mysql"`
+
+	// mapping nilai huruf ke bobot, contoh "A=4.0,AB=3.5,B=3.0", kosong berarti skala default
+	GradeScale string `envconfig:"GRADE_SCALE"`
+
+	// persentase kehadiran minimal, mahasiswa di bawah nilai ini ditandai di laporan
+	AttendanceThreshold float64 `envconfig:"ATTENDANCE_THRESHOLD" default:"75"`
+
+	// lama hasil statistik disimpan di cache, 0 berarti tanpa cache
+	StatsCacheTTL time.Duration `envconfig:"STATS_CACHE_TTL" default:"1m"`
+}
+
+// LoadConfig membaca konfigurasi dari environment variable
+func LoadConfig() (*Config, error) {
+	var c Config
+	if err := envconfig.Process("", &c); err != nil {
+		return nil, err
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// validate menolak interval job background yang tidak positif (time.NewTicker panic untuk nilai <= 0)
+// dan driver s3 tanpa nama bucket
+func (c *Config) validate() error {
+	if c.StorageDriver == "s3" && c.S3BucketName == "" {
+		return errors.New("S3_BUCKET_NAME is required when STORAGE_DRIVER is s3")
+	}
+
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"TRASH_RETENTION", c.TrashRetention},
+		{"TRASH_PURGE_INTERVAL", c.TrashPurgeInterval},
+		{"UPLOAD_SWEEP_INTERVAL", c.UploadSweepInterval},
+	}
+	for _, d := range durations {
+		if d.value <= 0 {
+			return fmt.Errorf("%s must be greater than 0, got %s", d.name, d.value)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,193 @@
+package customfield
+
+import (
+	"encoding/json"
+	"fmt"
+	"ginDatabaseMhs/model/entity"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	TypeText    = "text"
+	TypeNumber  = "number"
+	TypeDate    = "date"
+	TypeBoolean = "boolean"
+	TypeSelect  = "select"
+)
+
+var Types = []string{TypeText, TypeNumber, TypeDate, TypeBoolean, TypeSelect}
+
+// key dipakai langsung di path JSON, jadi hanya huruf kecil, angka dan underscore
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// CheckDefinition memeriksa definisi field sebelum disimpan
+func CheckDefinition(field entity.CustomField) error {
+	if !keyPattern.MatchString(field.Key) {
+		return fmt.Errorf("key must start with a letter and contain only lowercase letters, digits and underscores (max 50)")
+	}
+	switch field.Type {
+	case TypeSelect:
+		if len(field.Options) == 0 {
+			return fmt.Errorf("select field %q must have options", field.Key)
+		}
+	case TypeText, TypeNumber, TypeDate, TypeBoolean:
+		if len(field.Options) > 0 {
+			return fmt.Errorf("options are only allowed for select fields")
+		}
+	default:
+		return fmt.Errorf("type must be one of %s", strings.Join(Types, ", "))
+	}
+	if field.Pattern != "" {
+		if field.Type != TypeText {
+			return fmt.Errorf("pattern is only allowed for text fields")
+		}
+		if _, err := regexp.Compile(field.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %v", err)
+		}
+	}
+	return nil
+}
+
+// Merge menggabungkan nilai lama dengan input, nilai null di input menghapus key.
+// Key lama yang definisinya sudah dihapus ikut dibuang
+func Merge(fields []entity.CustomField, current entity.JSON, input map[string]interface{}) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if len(current) > 0 {
+		if err := json.Unmarshal(current, &values); err != nil {
+			return nil, err
+		}
+	}
+	for key := range values {
+		if find(fields, key) == nil {
+			delete(values, key)
+		}
+	}
+	for key, value := range input {
+		if value == nil {
+			delete(values, key)
+			continue
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// Validate memeriksa values terhadap definisi dan mengembalikan nilai yang sudah dinormalisasi:
+// number menjadi float64, date menjadi YYYY-MM-DD dan text dipangkas spasinya
+func Validate(fields []entity.CustomField, values map[string]interface{}) (map[string]interface{}, []string) {
+	var errs []string
+	out := make(map[string]interface{}, len(values))
+	invalid := map[string]bool{}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field := find(fields, key)
+		if field == nil {
+			errs = append(errs, fmt.Sprintf("%s: unknown custom field", key))
+			continue
+		}
+		value, err := normalize(*field, values[key])
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", key, err))
+			invalid[key] = true
+			continue
+		}
+		if value != nil {
+			out[key] = value
+		}
+	}
+	for _, field := range fields {
+		if _, ok := out[field.Key]; field.Required && !ok && !invalid[field.Key] {
+			errs = append(errs, fmt.Sprintf("%s: is required", field.Key))
+		}
+	}
+	return out, errs
+}
+
+// Column ekspresi SQL untuk membaca nilai key dari kolom custom_fields
+func Column(field entity.CustomField) string {
+	column := fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(custom_fields, '$.%s'))", field.Key)
+	if field.Type == TypeNumber {
+		return "CAST(" + column + " AS DECIMAL(20,6))"
+	}
+	return column
+}
+
+// normalize hasil nil berarti nilai kosong dan tidak disimpan
+func normalize(field entity.CustomField, value interface{}) (interface{}, error) {
+	switch field.Type {
+	case TypeNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			if strings.TrimSpace(v) == "" {
+				return nil, nil
+			}
+			n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("must be a number")
+			}
+			return n, nil
+		}
+		return nil, fmt.Errorf("must be a number")
+	case TypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("must be true or false")
+			}
+			return b, nil
+		}
+		return nil, fmt.Errorf("must be true or false")
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("must be a string")
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	switch field.Type {
+	case TypeDate:
+		date, err := entity.ParseDate(s)
+		if err != nil {
+			return nil, err
+		}
+		return date.String(), nil
+	case TypeSelect:
+		for _, option := range field.Options {
+			if option == s {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of %s", strings.Join(field.Options, ", "))
+	}
+	if field.Pattern != "" {
+		if matched, _ := regexp.MatchString(field.Pattern, s); !matched {
+			return nil, fmt.Errorf("does not match pattern %s", field.Pattern)
+		}
+	}
+	return s, nil
+}
+
+func find(fields []entity.CustomField, key string) *entity.CustomField {
+	for i := range fields {
+		if fields[i].Key == key {
+			return &fields[i]
+		}
+	}
+	return nil
+}
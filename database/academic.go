@@ -0,0 +1,157 @@
+package database
+
+import (
+	"errors"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/pagination"
+	"ginDatabaseMhs/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (t MahasiswaRepository) CreateCourse(course *entity.Course) error {
+	return t.DB.Create(course).Error
+}
+
+func (t MahasiswaRepository) GetCoursesByUser(userID int64, page pagination.Params) ([]entity.Course, error) {
+	var courses []entity.Course
+	if err := t.DB.Where("user_id = ?", userID).Scopes(page.Scope).Find(&courses).Error; err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+func (t MahasiswaRepository) GetCourseByID(courseID, userID int64) (*entity.Course, error) {
+	var course entity.Course
+	result := t.DB.Where("id = ? AND user_id = ?", courseID, userID).First(&course)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &course, result.Error
+}
+
+func (t MahasiswaRepository) GetCourseByCode(userID int64, code string) (*entity.Course, error) {
+	var course entity.Course
+	result := t.DB.Where("user_id = ? AND code = ?", userID, code).First(&course)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &course, result.Error
+}
+
+func (t MahasiswaRepository) UpdateCourse(courseID, userID int64, updates map[string]interface{}) (int64, error) {
+	result := t.DB.Model(&entity.Course{}).Where("id = ? AND user_id = ?", courseID, userID).Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteCourse mengembalikan repository.ErrInUse kalau mata kuliah sudah punya enrollment atau pertemuan
+func (t MahasiswaRepository) DeleteCourse(courseID, userID int64) (int64, error) {
+	var rowsAffected int64
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		var used, sessions int64
+		if err := tx.Model(&entity.Enrollment{}).Where("course_id = ?", courseID).Count(&used).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.ClassSession{}).Where("course_id = ?", courseID).Count(&sessions).Error; err != nil {
+			return err
+		}
+		if used > 0 || sessions > 0 {
+			return repository.ErrInUse
+		}
+		result := tx.Where("id = ? AND user_id = ?", courseID, userID).Delete(&entity.Course{})
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	return rowsAffected, err
+}
+
+func (t MahasiswaRepository) CreateSemester(semester *entity.Semester) error {
+	return t.DB.Create(semester).Error
+}
+
+func (t MahasiswaRepository) GetSemestersByUser(userID int64, page pagination.Params) ([]entity.Semester, error) {
+	var semesters []entity.Semester
+	if err := t.DB.Where("user_id = ?", userID).Scopes(page.Scope).Find(&semesters).Error; err != nil {
+		return nil, err
+	}
+	return semesters, nil
+}
+
+func (t MahasiswaRepository) GetSemesterByID(semesterID, userID int64) (*entity.Semester, error) {
+	var semester entity.Semester
+	result := t.DB.Where("id = ? AND user_id = ?", semesterID, userID).First(&semester)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &semester, result.Error
+}
+
+func (t MahasiswaRepository) UpdateSemester(semesterID, userID int64, updates map[string]interface{}) (int64, error) {
+	result := t.DB.Model(&entity.Semester{}).Where("id = ? AND user_id = ?", semesterID, userID).Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteSemester mengembalikan repository.ErrInUse kalau semester sudah punya enrollment atau pertemuan
+func (t MahasiswaRepository) DeleteSemester(semesterID, userID int64) (int64, error) {
+	var rowsAffected int64
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		var used, sessions int64
+		if err := tx.Model(&entity.Enrollment{}).Where("semester_id = ?", semesterID).Count(&used).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.ClassSession{}).Where("semester_id = ?", semesterID).Count(&sessions).Error; err != nil {
+			return err
+		}
+		if used > 0 || sessions > 0 {
+			return repository.ErrInUse
+		}
+		result := tx.Where("id = ? AND user_id = ?", semesterID, userID).Delete(&entity.Semester{})
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	return rowsAffected, err
+}
+
+func (t MahasiswaRepository) CreateEnrollment(enrollment *entity.Enrollment) error {
+	return t.DB.Create(enrollment).Error
+}
+
+// GetEnrollmentsByRecord semua enrollment satu mahasiswa beserta mata kuliah, semester dan nilainya
+func (t MahasiswaRepository) GetEnrollmentsByRecord(recordID int64) ([]entity.Enrollment, error) {
+	var enrollments []entity.Enrollment
+	err := t.DB.Preload("Course").Preload("Semester").Preload("Grade").
+		Where("record_id = ?", recordID).
+		Order("semester_id, course_id").
+		Find(&enrollments).Error
+	if err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+func (t MahasiswaRepository) GetEnrollmentByID(enrollmentID int64) (*entity.Enrollment, error) {
+	var enrollment entity.Enrollment
+	result := t.DB.Preload("Course").Preload("Semester").Preload("Grade").First(&enrollment, enrollmentID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &enrollment, result.Error
+}
+
+func (t MahasiswaRepository) DeleteEnrollment(enrollmentID int64) (int64, error) {
+	result := t.DB.Delete(&entity.Enrollment{}, enrollmentID)
+	return result.RowsAffected, result.Error
+}
+
+// SaveGrade membuat atau mengganti nilai satu enrollment
+func (t MahasiswaRepository) SaveGrade(grade *entity.Grade) error {
+	return t.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "enrollment_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"letter", "score", "updated_at"}),
+	}).Create(grade).Error
+}
+
+func (t MahasiswaRepository) DeleteGrade(enrollmentID int64) (int64, error) {
+	result := t.DB.Where("enrollment_id = ?", enrollmentID).Delete(&entity.Grade{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"ginDatabaseMhs/audit"
+	"ginDatabaseMhs/customfield"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/pagination"
+	"ginDatabaseMhs/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetAllRecords data mahasiswa dari semua pemilik, ownerIDs kosong berarti tidak dibatasi pemilik.
+// adminID dipakai untuk filter tag sama seperti pada GetAllUserByID
+func (t MahasiswaRepository) GetAllRecords(adminID int64, ownerIDs []int64, filter request.MahasiswaFilter, page pagination.Params, proj request.Projection) ([]entity.User_data, error) {
+	var dataMhs []entity.User_data
+	err := t.DB.Scopes(ownerScope(ownerIDs), criteriaScope(adminID, filter), projectionScope(adminID, proj), page.Scope).
+		Find(&dataMhs).Error
+	if err != nil {
+		return nil, err
+	}
+	return dataMhs, nil
+}
+
+func (t MahasiswaRepository) CountAllRecords(adminID int64, ownerIDs []int64, filter request.MahasiswaFilter) (int64, error) {
+	var total int64
+	err := t.DB.Model(&entity.User_data{}).Scopes(ownerScope(ownerIDs), criteriaScope(adminID, filter)).Count(&total).Error
+	return total, err
+}
+
+// GetRecordByID satu data mahasiswa tanpa melihat pemiliknya, beserta lampiran dan tag
+func (t MahasiswaRepository) GetRecordByID(mhsID int64) (*entity.User_data, error) {
+	var data entity.User_data
+	result := t.DB.Preload("Attachments", orderedAttachments).Preload("Tags").Where("id = ?", mhsID).First(&data)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &data, result.Error
+}
+
+func (t MahasiswaRepository) GetUsersByIDs(ids []int64) ([]entity.User, error) {
+	var users []entity.User
+	if err := t.DB.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateUserRole mengganti role pengguna, hasil nil tanpa error berarti pengguna tidak ditemukan
+func (t MahasiswaRepository) UpdateUserRole(userID int64, role string) (*entity.User, error) {
+	var user entity.User
+	if err := t.DB.Where("id = ?", userID).Take(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := t.DB.Model(&user).Update("role", role).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ReassignOwner memindahkan data ke pemilik baru dalam satu transaksi. Lampiran terhubung lewat id data
+// jadi ikut pindah bersama datanya, sedangkan tag milik pemilik lama dilepas karena tidak terlihat oleh
+// pemilik baru dan akses share pemilik baru dihapus karena sudah tidak diperlukan. Nilai custom field
+// yang tidak sah menurut definisi pemilik baru dibuang.
+// Mengembalikan repository.ErrInUse kalau data masih punya enrollment atau kehadiran, karena keduanya
+// menunjuk mata kuliah, semester dan pertemuan milik pemilik lama.
+// Hasil nil tanpa error berarti data tidak ditemukan
+func (t MahasiswaRepository) ReassignOwner(mhsID, ownerID int64, actor audit.Actor) (*entity.User_data, error) {
+	var data entity.User_data
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		var current entity.User_data
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", mhsID).First(&current).Error
+		if err != nil {
+			return err
+		}
+
+		var enrollments, attendances int64
+		if err := tx.Model(&entity.Enrollment{}).Where("record_id = ?", mhsID).Count(&enrollments).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.Attendance{}).Where("record_id = ?", mhsID).Count(&attendances).Error; err != nil {
+			return err
+		}
+		if enrollments > 0 || attendances > 0 {
+			return repository.ErrInUse
+		}
+
+		customFields, err := ownerCustomFields(tx, ownerID, current.CustomFields)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&entity.User_data{}).Where("id = ?", mhsID).
+			Updates(map[string]interface{}{"user_id": ownerID, "custom_fields": customFields}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("record_id = ? AND tag_id IN (SELECT id FROM tags WHERE user_id = ?)", mhsID, current.UserID).
+			Delete(&entity.RecordTag{}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("record_id = ? AND user_id = ?", mhsID, ownerID).Delete(&entity.RecordShare{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Preload("Attachments", orderedAttachments).Preload("Tags").First(&data, mhsID).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, actor, recordEntry(entity.AuditActionReassign, &current, &data))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// ownerCustomFields nilai custom field yang tetap sah menurut definisi milik ownerID, sisanya dibuang
+func ownerCustomFields(tx *gorm.DB, ownerID int64, current entity.JSON) (entity.JSON, error) {
+	if len(current) == 0 {
+		return current, nil
+	}
+	var fields []entity.CustomField
+	if err := tx.Where("user_id = ?", ownerID).Find(&fields).Error; err != nil {
+		return nil, err
+	}
+	values, err := customfield.Merge(fields, current, nil)
+	if err != nil {
+		return nil, err
+	}
+	// nilai yang tidak lolos validasi tidak ada di hasil Validate, field wajib yang kosong dibiarkan
+	values, _ = customfield.Validate(fields, values)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	raw, err := json.Marshal(values)
+	return entity.JSON(raw), err
+}
+
+func ownerScope(ownerIDs []int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(ownerIDs) == 0 {
+			return db
+		}
+		return db.Where("user_id IN ?", ownerIDs)
+	}
+}
@@ -0,0 +1,343 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"ginDatabaseMhs/audit"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// orderedAttachments dipakai di Preload supaya lampiran selalu tampil sesuai attachment_order
+func orderedAttachments(db *gorm.DB) *gorm.DB {
+	return db.Order("attachment_order")
+}
+
+func (t MahasiswaRepository) GetAttachmentByID(id int64) (*entity.Attachment, error) {
+	var attachment entity.Attachment
+	err := t.DB.First(&attachment, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (t MahasiswaRepository) GetAttachmentsByRecord(mhsID int64) ([]entity.Attachment, error) {
+	var attachments []entity.Attachment
+	err := t.DB.Where("user_id = ?", mhsID).Scopes(orderedAttachments).Find(&attachments).Error
+	return attachments, err
+}
+
+// DeleteAttachment menghapus lampiran lalu menggeser urutan lampiran setelahnya supaya tetap 1..n.
+// File di storage dihapus setelah transaksi berhasil
+func (t MahasiswaRepository) DeleteAttachment(ctx context.Context, id int64, actor audit.Actor) (*entity.Attachment, error) {
+	var attachment entity.Attachment
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&attachment, id).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&attachment).Error; err != nil {
+			return err
+		}
+		// ORDER BY supaya unique (user_id, attachment_order) tidak bentrok saat digeser satu per satu
+		err = tx.Exec("UPDATE attachments SET attachment_order = attachment_order - 1 "+
+			"WHERE user_id = ? AND attachment_order > ? ORDER BY attachment_order",
+			attachment.UserID, attachment.AttachmentOrder).Error
+		if err != nil {
+			return err
+		}
+		return writeAudit(tx, actor, attachmentEntry(entity.AuditActionDelete, attachment.UserID, &attachment, nil))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t.removeAttachmentFiles(ctx, attachment)
+	return &attachment, nil
+}
+
+// ReplaceAttachmentFile mengganti file lampiran (beserta variant-nya) tanpa mengubah id dan urutannya.
+// Kalau lampiran sudah berubah atau terhapus sejak dibaca, file baru dibuang dan gorm.ErrRecordNotFound dikembalikan
+func (t MahasiswaRepository) ReplaceAttachmentFile(ctx context.Context, attachment *entity.Attachment, file repository.AttachmentFile, actor audit.Actor) (*entity.Attachment, error) {
+	stored, err := t.storeAttachmentFile(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	var replaced entity.Attachment
+	err = t.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Attachment{}).
+			Where("id = ? AND path = ?", attachment.ID, attachment.Path).
+			Updates(map[string]interface{}{
+				"path":         stored.Path,
+				"storage":      stored.Storage,
+				"file_name":    stored.FileName,
+				"content_type": stored.ContentType,
+				"size":         stored.Size,
+				"variants":     stored.Variants,
+				"timestamp":    stored.Timestamp,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.First(&replaced, attachment.ID).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, actor, attachmentEntry(entity.AuditActionUpdate, replaced.UserID, attachment, &replaced))
+	})
+	if err != nil {
+		t.removeAttachmentFiles(context.Background(), *stored)
+		return nil, err
+	}
+	t.removeAttachmentFiles(ctx, *attachment)
+	return &replaced, nil
+}
+
+// storeAttachmentFile menyimpan file dengan key baru lalu variant-nya di sebelahnya, upload berhenti kalau ctx
+// dibatalkan (client putus). Semua yang sudah tersimpan dihapus lagi kalau salah satunya gagal
+func (t MahasiswaRepository) storeAttachmentFile(ctx context.Context, file repository.AttachmentFile) (*entity.Attachment, error) {
+	// bikin nama file yang uniq untuk menghindari konflik
+	key := uuid.NewString() + strings.ToLower(filepath.Ext(file.Name))
+	info, err := t.Storage.Primary().Put(ctx, key, file.Reader, file.Size, file.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &entity.Attachment{
+		Path:        info.Key,
+		Storage:     t.Storage.Default,
+		FileName:    filepath.Base(file.Name),
+		ContentType: file.ContentType,
+		Size:        info.Size,
+		Timestamp:   time.Now(),
+	}
+	names := make([]string, 0, len(file.Variants))
+	for name := range file.Variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data := file.Variants[name]
+		_, err := t.Storage.Primary().Put(ctx, attachment.VariantKey(name), bytes.NewReader(data), int64(len(data)), file.ContentType)
+		if err != nil {
+			t.removeAttachmentFiles(context.Background(), *attachment)
+			return nil, err
+		}
+		attachment.Variants = append(attachment.Variants, name)
+	}
+	return attachment, nil
+}
+
+// removeAttachmentFiles menghapus file lampiran dan semua variant-nya, kegagalan cukup di log.
+// File yang baru disimpan lalu batal dipakai dihapus dengan context.Background() supaya tidak tertinggal
+// walaupun client sudah putus
+func (t MahasiswaRepository) removeAttachmentFiles(ctx context.Context, attachment entity.Attachment) {
+	keys := []string{attachment.Path}
+	for _, variant := range attachment.Variants {
+		keys = append(keys, attachment.VariantKey(variant))
+	}
+	for _, key := range keys {
+		if err := t.removeStoredFile(ctx, attachment.Storage, key); err != nil {
+			logrus.Errorf("failed to remove attachment file %s: %v", key, err)
+		}
+	}
+}
+
+// ReorderAttachments mengurutkan ulang lampiran sesuai ids. ids harus berisi semua lampiran data tepat satu kali,
+// kalau tidak repository.ErrAttachmentOrder dikembalikan. Hanya lampiran yang urutannya berubah yang tercatat di history
+func (t MahasiswaRepository) ReorderAttachments(mhsID int64, ids []int64, actor audit.Actor) ([]entity.Attachment, error) {
+	var attachments []entity.Attachment
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		var before []entity.Attachment
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", mhsID).Find(&before).Error
+		if err != nil {
+			return err
+		}
+		current := make([]int64, 0, len(before))
+		previous := make(map[int64]entity.Attachment, len(before))
+		for _, attachment := range before {
+			current = append(current, attachment.ID)
+			previous[attachment.ID] = attachment
+		}
+		if !sameIDs(current, ids) {
+			return repository.ErrAttachmentOrder
+		}
+
+		// dibuat negatif dulu supaya nilai baru tidak bentrok dengan unique (user_id, attachment_order)
+		err = tx.Model(&entity.Attachment{}).Where("user_id = ?", mhsID).
+			Update("attachment_order", gorm.Expr("-attachment_order")).Error
+		if err != nil {
+			return err
+		}
+		for i, id := range ids {
+			err := tx.Model(&entity.Attachment{}).Where("id = ?", id).
+				Update("attachment_order", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("user_id = ?", mhsID).Scopes(orderedAttachments).Find(&attachments).Error; err != nil {
+			return err
+		}
+
+		var entries []audit.Entry
+		for i := range attachments {
+			if old, ok := previous[attachments[i].ID]; ok && old.AttachmentOrder != attachments[i].AttachmentOrder {
+				entries = append(entries, attachmentEntry(entity.AuditActionUpdate, mhsID, &old, &attachments[i]))
+			}
+		}
+		return writeAudit(tx, actor, entries...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// sameIDs true kalau b berisi id yang sama persis dengan a tanpa duplikat
+func sameIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[int64]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
+
+// appendAttachment mencatat lampiran sebagai urutan terakhir dari datanya, dipanggil di dalam transaksi
+func appendAttachment(tx *gorm.DB, attachment *entity.Attachment) error {
+	// baris data dikunci supaya upload bersamaan tidak mendapat urutan yang sama
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		First(&entity.User_data{}, attachment.UserID).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&entity.Attachment{}).Where("user_id = ?", attachment.UserID).
+		Select("COALESCE(MAX(attachment_order), 0) + 1").Scan(&attachment.AttachmentOrder).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(attachment).Error
+}
+
+func (t MahasiswaRepository) CreatePendingUpload(upload *entity.PendingUpload) error {
+	return t.DB.Create(upload).Error
+}
+
+// GetPendingUpload upload yang belum selesai milik user, nil kalau key tidak dikenal
+func (t MahasiswaRepository) GetPendingUpload(key string, userID int64) (*entity.PendingUpload, error) {
+	var upload entity.PendingUpload
+	err := t.DB.Where("`key` = ? AND user_id = ?", key, userID).First(&upload).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (t MahasiswaRepository) DeletePendingUpload(id int64) error {
+	return t.DB.Delete(&entity.PendingUpload{}, id).Error
+}
+
+// CompletePendingUpload mengubah upload yang sudah diverifikasi menjadi lampiran. Kalau file diisi
+// (gambar hasil pipeline), file itu disimpan dengan key baru dan file mentah hasil upload langsung dihapus
+func (t MahasiswaRepository) CompletePendingUpload(ctx context.Context, upload *entity.PendingUpload, file *repository.AttachmentFile, actor audit.Actor) (*entity.Attachment, error) {
+	attachment := &entity.Attachment{
+		Path:        upload.Key,
+		Storage:     t.Storage.Default,
+		FileName:    upload.FileName,
+		ContentType: upload.ContentType,
+		Size:        upload.Size,
+		Timestamp:   time.Now(),
+	}
+	if file != nil {
+		stored, err := t.storeAttachmentFile(ctx, *file)
+		if err != nil {
+			return nil, err
+		}
+		attachment = stored
+	}
+	attachment.UserID = upload.RecordID
+
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		// hapus dulu supaya key yang sama tidak bisa diselesaikan dua kali
+		result := tx.Delete(&entity.PendingUpload{}, upload.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := appendAttachment(tx, attachment); err != nil {
+			return err
+		}
+		return writeAudit(tx, actor, attachmentEntry(entity.AuditActionCreate, upload.RecordID, nil, attachment))
+	})
+	if err != nil {
+		if file != nil {
+			t.removeAttachmentFiles(context.Background(), *attachment)
+		}
+		return nil, err
+	}
+	if file != nil {
+		if err := t.removeStoredFile(ctx, t.Storage.Default, upload.Key); err != nil {
+			logrus.Errorf("failed to remove uploaded file %s: %v", upload.Key, err)
+		}
+	}
+	return attachment, nil
+}
+
+// PurgeExpiredUploads menghapus pending upload yang kadaluarsa sebelum waktu before beserta filenya.
+// Baris dihapus satu per satu dan file hanya dihapus kalau barisnya benar-benar terhapus di sini,
+// jadi upload yang sedang diselesaikan bersamaan tidak kehilangan filenya
+func (t MahasiswaRepository) PurgeExpiredUploads(before time.Time) ([]entity.PendingUpload, error) {
+	var expired []entity.PendingUpload
+	if err := t.DB.Where("expires_at < ?", before).Find(&expired).Error; err != nil {
+		return nil, err
+	}
+
+	purged := make([]entity.PendingUpload, 0, len(expired))
+	for _, upload := range expired {
+		result := t.DB.Delete(&entity.PendingUpload{}, upload.ID)
+		if result.Error != nil {
+			return purged, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		if err := t.removeStoredFile(context.Background(), t.Storage.Default, upload.Key); err != nil {
+			logrus.Errorf("failed to remove uploaded file %s: %v", upload.Key, err)
+		}
+		purged = append(purged, upload)
+	}
+	return purged, nil
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"errors"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (t MahasiswaRepository) CreateClassSession(session *entity.ClassSession) error {
+	return t.DB.Create(session).Error
+}
+
+// GetClassSessions pertemuan milik user, courseID dan semesterID 0 berarti tidak difilter
+func (t MahasiswaRepository) GetClassSessions(userID, courseID, semesterID int64, page pagination.Params) ([]entity.ClassSession, error) {
+	var sessions []entity.ClassSession
+	db := t.DB.Where("user_id = ?", userID)
+	if courseID != 0 {
+		db = db.Where("course_id = ?", courseID)
+	}
+	if semesterID != 0 {
+		db = db.Where("semester_id = ?", semesterID)
+	}
+	if err := db.Scopes(page.Scope).Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (t MahasiswaRepository) GetClassSessionByID(sessionID, userID int64) (*entity.ClassSession, error) {
+	var session entity.ClassSession
+	result := t.DB.Where("id = ? AND user_id = ?", sessionID, userID).First(&session)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &session, result.Error
+}
+
+func (t MahasiswaRepository) GetClassSessionByMeeting(courseID, semesterID int64, meetingNumber int) (*entity.ClassSession, error) {
+	var session entity.ClassSession
+	result := t.DB.Where("course_id = ? AND semester_id = ? AND meeting_number = ?", courseID, semesterID, meetingNumber).First(&session)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &session, result.Error
+}
+
+func (t MahasiswaRepository) UpdateClassSession(sessionID, userID int64, updates map[string]interface{}) (int64, error) {
+	result := t.DB.Model(&entity.ClassSession{}).Where("id = ? AND user_id = ?", sessionID, userID).Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteClassSession kehadiran di pertemuan tersebut ikut terhapus
+func (t MahasiswaRepository) DeleteClassSession(sessionID, userID int64) (int64, error) {
+	result := t.DB.Where("id = ? AND user_id = ?", sessionID, userID).Delete(&entity.ClassSession{})
+	return result.RowsAffected, result.Error
+}
+
+// GetEnrolledRecordIDs id mahasiswa milik ownerID yang mengambil mata kuliah di semester tersebut
+func (t MahasiswaRepository) GetEnrolledRecordIDs(ownerID, courseID, semesterID int64) ([]int64, error) {
+	var ids []int64
+	err := t.DB.Model(&entity.Enrollment{}).
+		Where("course_id = ? AND semester_id = ?", courseID, semesterID).
+		Where("record_id IN (SELECT id FROM user_data WHERE user_id = ? AND deleted_at IS NULL)", ownerID).
+		Pluck("record_id", &ids).Error
+	return ids, err
+}
+
+// SaveAttendances menyimpan kehadiran satu pertemuan sekaligus, data yang sudah ada diganti
+func (t MahasiswaRepository) SaveAttendances(attendances []entity.Attendance) error {
+	if len(attendances) == 0 {
+		return nil
+	}
+	return t.DB.Transaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"status", "note", "updated_at"}),
+		}).CreateInBatches(attendances, 500).Error
+	})
+}
+
+func (t MahasiswaRepository) GetAttendanceBySession(sessionID int64) ([]entity.Attendance, error) {
+	var attendances []entity.Attendance
+	if err := t.DB.Where("session_id = ?", sessionID).Order("record_id").Find(&attendances).Error; err != nil {
+		return nil, err
+	}
+	return attendances, nil
+}
+
+// GetAttendanceStats rekap kehadiran per mahasiswa per mata kuliah berdasarkan enrollment,
+// nilai 0 pada courseID, semesterID atau recordID berarti tidak difilter
+func (t MahasiswaRepository) GetAttendanceStats(courseID, semesterID, recordID int64) ([]entity.AttendanceStat, error) {
+	db := t.DB.Table("enrollments e").
+		Select(`e.record_id, u.name, e.course_id, e.semester_id,
+			COUNT(DISTINCT s.id) AS sessions,
+			COALESCE(SUM(a.status = ?), 0) AS present,
+			COALESCE(SUM(a.status = ?), 0) AS absent,
+			COALESCE(SUM(a.status = ?), 0) AS sick,
+			COALESCE(SUM(a.status = ?), 0) AS permitted`,
+			entity.AttendancePresent, entity.AttendanceAbsent, entity.AttendanceSick, entity.AttendancePermitted).
+		Joins("JOIN user_data u ON u.id = e.record_id AND u.deleted_at IS NULL").
+		Joins("LEFT JOIN class_sessions s ON s.course_id = e.course_id AND s.semester_id = e.semester_id").
+		Joins("LEFT JOIN attendances a ON a.session_id = s.id AND a.record_id = e.record_id")
+	if courseID != 0 {
+		db = db.Where("e.course_id = ?", courseID)
+	}
+	if semesterID != 0 {
+		db = db.Where("e.semester_id = ?", semesterID)
+	}
+	if recordID != 0 {
+		db = db.Where("e.record_id = ?", recordID)
+	}
+
+	var stats []entity.AttendanceStat
+	err := db.Group("e.record_id, u.name, e.course_id, e.semester_id").
+		Order("e.course_id, e.semester_id, u.name, e.record_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"errors"
+	"ginDatabaseMhs/audit"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// writeAudit mencatat perubahan ke history di dalam transaksi yang sama dengan perubahannya,
+// jadi history tidak pernah hilang atau tercatat untuk perubahan yang dibatalkan
+func writeAudit(tx *gorm.DB, actor audit.Actor, entries ...audit.Entry) error {
+	for _, e := range entries {
+		log, err := audit.NewLog(actor, e)
+		if err != nil {
+			return err
+		}
+
+		// version dihitung per entity, row terakhir di lock supaya tidak bentrok
+		var last entity.AuditLog
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("entity_type = ? AND entity_id = ?", log.EntityType, log.EntityID).
+			Order("version DESC").
+			Limit(1).
+			Find(&last).Error
+		if err != nil {
+			return err
+		}
+
+		log.Version = last.Version + 1
+		if err := tx.Create(log).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordSnapshot data tanpa relasi untuk history, supaya before dan after bisa dibandingkan
+// walaupun dibaca dengan preload yang berbeda
+func recordSnapshot(data entity.User_data) *entity.User_data {
+	data.Attachments = nil
+	data.Tags = nil
+	return &data
+}
+
+func recordEntry(action string, before, after *entity.User_data) audit.Entry {
+	e := audit.Entry{EntityType: entity.AuditEntityUserData, Action: action}
+	if before != nil {
+		e.EntityID, e.Before = before.ID, recordSnapshot(*before)
+	}
+	if after != nil {
+		e.EntityID, e.After = after.ID, recordSnapshot(*after)
+	}
+	e.RecordID = e.EntityID
+	return e
+}
+
+func attachmentEntry(action string, recordID int64, before, after *entity.Attachment) audit.Entry {
+	e := audit.Entry{EntityType: entity.AuditEntityAttachment, RecordID: recordID, Action: action}
+	if before != nil {
+		e.EntityID, e.Before = before.ID, before
+	}
+	if after != nil {
+		e.EntityID, e.After = after.ID, after
+	}
+	return e
+}
+
+func (t MahasiswaRepository) GetAuditLogsByRecord(recordID int64, page pagination.Params) ([]entity.AuditLog, error) {
+	var logs []entity.AuditLog
+	result := t.DB.Where("record_id = ?", recordID).Scopes(page.Scope).Find(&logs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return logs, nil
+}
+
+func (t MahasiswaRepository) GetAuditLogVersion(entityType string, entityID int64, version int) (*entity.AuditLog, error) {
+	var log entity.AuditLog
+	result := t.DB.Where("entity_type = ? AND entity_id = ? AND version = ?", entityType, entityID, version).First(&log)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &log, result.Error
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"errors"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/pagination"
+	"gorm.io/gorm"
+)
+
+// commentScope comment beserta username penulisnya, memakai subquery supaya kolom id tidak ambigu untuk keyset
+func commentScope(db *gorm.DB) *gorm.DB {
+	return db.Select("comments.*, (SELECT username FROM users WHERE users.id = comments.author_id) AS author")
+}
+
+func (t MahasiswaRepository) CreateComment(comment *entity.Comment) error {
+	return t.DB.Create(comment).Error
+}
+
+// GetCommentsByRecord comment shared ditambah comment private milik viewerID
+func (t MahasiswaRepository) GetCommentsByRecord(recordID, viewerID int64, page pagination.Params) ([]entity.Comment, error) {
+	var comments []entity.Comment
+	err := t.DB.Scopes(commentScope, page.Scope).
+		Where("record_id = ?", recordID).
+		Where("(visibility = ? OR author_id = ?)", entity.CommentVisibilityShared, viewerID).
+		Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (t MahasiswaRepository) GetCommentByID(commentID int64) (*entity.Comment, error) {
+	var comment entity.Comment
+	result := t.DB.Scopes(commentScope).Where("id = ?", commentID).First(&comment)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &comment, result.Error
+}
+
+// UpdateComment isi lama disimpan sebagai revisi sebelum comment diubah
+func (t MahasiswaRepository) UpdateComment(comment *entity.Comment, body, visibility string, editorID int64) error {
+	return t.DB.Transaction(func(tx *gorm.DB) error {
+		revision := &entity.CommentRevision{
+			CommentID:  comment.ID,
+			Body:       comment.Body,
+			Visibility: comment.Visibility,
+			EditedBy:   editorID,
+		}
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.Comment{}).Where("id = ?", comment.ID).Updates(map[string]interface{}{
+			"body":       body,
+			"visibility": visibility,
+			"edit_count": gorm.Expr("edit_count + 1"),
+		}).Error
+	})
+}
+
+func (t MahasiswaRepository) DeleteComment(commentID int64) (int64, error) {
+	var rowsAffected int64
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("comment_id = ?", commentID).Delete(&entity.CommentRevision{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entity.Comment{}, commentID)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	return rowsAffected, err
+}
+
+// GetCommentRevisions revisi comment dari yang paling lama
+func (t MahasiswaRepository) GetCommentRevisions(commentID int64) ([]entity.CommentRevision, error) {
+	var revisions []entity.CommentRevision
+	if err := t.DB.Where("comment_id = ?", commentID).Order("id").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"errors"
+	"ginDatabaseMhs/model/entity"
+	"gorm.io/gorm"
+)
+
+func (t MahasiswaRepository) CreateCustomField(field *entity.CustomField) error {
+	return t.DB.Create(field).Error
+}
+
+// GetCustomFieldsByUser semua definisi custom field milik user, urut sesuai waktu dibuat
+func (t MahasiswaRepository) GetCustomFieldsByUser(userID int64) ([]entity.CustomField, error) {
+	var fields []entity.CustomField
+	if err := t.DB.Where("user_id = ?", userID).Order("id").Find(&fields).Error; err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func (t MahasiswaRepository) GetCustomFieldByID(fieldID int64) (*entity.CustomField, error) {
+	var field entity.CustomField
+	result := t.DB.Where("id = ?", fieldID).First(&field)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &field, result.Error
+}
+
+func (t MahasiswaRepository) GetCustomFieldByKey(userID int64, key string) (*entity.CustomField, error) {
+	var field entity.CustomField
+	result := t.DB.Where("user_id = ? AND `key` = ?", userID, key).First(&field)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &field, result.Error
+}
+
+func (t MahasiswaRepository) UpdateCustomField(fieldID, userID int64, updates map[string]interface{}) (int64, error) {
+	result := t.DB.Model(&entity.CustomField{}).Where("id = ? AND user_id = ?", fieldID, userID).Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteCustomField nilai yang sudah tersimpan di user_data tidak dihapus, tapi diabaikan saat data diubah
+func (t MahasiswaRepository) DeleteCustomField(fieldID, userID int64) (int64, error) {
+	result := t.DB.Where("id = ? AND user_id = ?", fieldID, userID).Delete(&entity.CustomField{})
+	return result.RowsAffected, result.Error
+}
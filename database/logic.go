@@ -4,29 +4,28 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"ginDatabaseMhs/audit"
 	"ginDatabaseMhs/model/entity"
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/google/uuid"
-	"github.com/sirupsen/logrus"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/pagination"
+	"ginDatabaseMhs/repository"
+	"ginDatabaseMhs/rsql"
+	"ginDatabaseMhs/storage"
 	"gorm.io/gorm"
-	"io"
-	"mime/multipart"
-	"os"
-	"path/filepath"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
 // adaptop pattern
 type MahasiswaRepository struct {
-	DB       *gorm.DB
-	S3Bucket *s3.Client
+	DB      *gorm.DB
+	Storage *storage.Set
 }
 
-func NewMahasiswaRepository(DB *gorm.DB, s3Bucket *s3.Client) *MahasiswaRepository {
+func NewMahasiswaRepository(DB *gorm.DB, store *storage.Set) *MahasiswaRepository {
 	return &MahasiswaRepository{
-		DB:       DB,
-		S3Bucket: s3Bucket,
+		DB:      DB,
+		Storage: store,
 	}
 }
 
@@ -37,9 +36,13 @@ func NewMahasiswaRepository(DB *gorm.DB, s3Bucket *s3.Client) *MahasiswaReposito
 //	return data, result.Error
 //}
 
-func (t *MahasiswaRepository) GetAllUsers() ([]entity.User, error) {
+func (t *MahasiswaRepository) GetAllUsers(where clause.Expression, page pagination.Params) ([]entity.User, error) {
 	var users []entity.User
-	if err := t.DB.Find(&users).Error; err != nil {
+	db := t.DB
+	if where != nil {
+		db = db.Where(where)
+	}
+	if err := db.Scopes(page.Scope).Find(&users).Error; err != nil {
 		return nil, err
 	}
 	return users, nil
@@ -64,11 +67,11 @@ func (t *MahasiswaRepository) DeleteUserByIDAndRole(userID int64, role string) e
 	return nil
 }
 
-func (t MahasiswaRepository) GetAllUserByID(UserID int64) ([]entity.User_data, error) {
+func (t MahasiswaRepository) GetAllUserByID(UserID int64, filter request.MahasiswaFilter, page pagination.Params, proj request.Projection) ([]entity.User_data, error) {
 	var data []entity.User_data
 
-	// Ambil semua data berdasarkan user_id
-	result := t.DB.Preload("Attachments").Where("user_id = ?", UserID).Find(&data)
+	// Ambil data berdasarkan user_id per halaman
+	result := t.DB.Scopes(filterScope(UserID, filter), page.Scope, projectionScope(UserID, proj)).Find(&data)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -76,9 +79,17 @@ func (t MahasiswaRepository) GetAllUserByID(UserID int64) ([]entity.User_data, e
 	return data, nil
 }
 
+func (t MahasiswaRepository) CountByUser(userID int64, filter request.MahasiswaFilter) (int64, error) {
+	var total int64
+	err := t.DB.Model(&entity.User_data{}).Scopes(filterScope(userID, filter)).Count(&total).Error
+	return total, err
+}
+
 func (t MahasiswaRepository) GetByID(mhsID, userID int64) (*entity.User_data, error) {
 	var data entity.User_data
-	result := t.DB.Preload("Attachments").Where("id = ? AND user_id = ?", mhsID, userID).First(&data)
+	result := t.DB.Preload("Attachments", orderedAttachments).Where("id = ?", mhsID).
+		Scopes(accessScope(userID, entity.SharePermissionViewer, entity.SharePermissionEditor)).
+		First(&data)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -86,10 +97,70 @@ func (t MahasiswaRepository) GetByID(mhsID, userID int64) (*entity.User_data, er
 	return &data, result.Error
 }
 
-func (t MahasiswaRepository) Create(mahasiswa *entity.User_data) (*entity.User_data, error) {
-	result := t.DB.Create(mahasiswa)
-	return mahasiswa, result.Error
+// GetByIDWithTrashed sama dengan GetByID tetapi data yang ada di trash ikut ditemukan
+func (t MahasiswaRepository) GetByIDWithTrashed(mhsID, userID int64) (*entity.User_data, error) {
+	var data entity.User_data
+	result := t.DB.Unscoped().Where("id = ?", mhsID).
+		Scopes(accessScope(userID, entity.SharePermissionViewer, entity.SharePermissionEditor)).
+		First(&data)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &data, result.Error
+}
+
+// GetProjectedByID sama dengan GetByID tetapi hanya mengambil kolom dan relasi yang diminta
+func (t MahasiswaRepository) GetProjectedByID(mhsID, userID int64, proj request.Projection) (*entity.User_data, error) {
+	var data entity.User_data
+	result := t.DB.Scopes(projectionScope(userID, proj), accessScope(userID, entity.SharePermissionViewer, entity.SharePermissionEditor)).
+		Where("id = ?", mhsID).
+		First(&data)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
 
+	return &data, result.Error
+}
+
+// GetByIDs mengambil beberapa data milik user sekaligus, urutan hasil tidak dijamin
+func (t MahasiswaRepository) GetByIDs(ids []int64, userID int64, proj request.Projection) ([]entity.User_data, error) {
+	var data []entity.User_data
+	if len(ids) == 0 {
+		return data, nil
+	}
+
+	result := t.DB.Scopes(projectionScope(userID, proj)).Where("id IN ? AND user_id = ?", ids, userID).Find(&data)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return data, nil
+}
+
+func (t MahasiswaRepository) Create(mahasiswa *entity.User_data, actor audit.Actor) (*entity.User_data, error) {
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(mahasiswa).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, actor, recordEntry(entity.AuditActionCreate, nil, mahasiswa))
+	})
+	return mahasiswa, err
+}
+
+// CreateBatch menyimpan banyak data sekaligus dalam satu transaksi, gagal satu berarti gagal semua
+func (t MahasiswaRepository) CreateBatch(data []entity.User_data, actor audit.Actor) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return t.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(&data, 100).Error; err != nil {
+			return err
+		}
+		entries := make([]audit.Entry, 0, len(data))
+		for i := range data {
+			entries = append(entries, recordEntry(entity.AuditActionCreate, nil, &data[i]))
+		}
+		return writeAudit(tx, actor, entries...)
+	})
 }
 
 func (t MahasiswaRepository) GetMahasiswaByNameAndEmail(name, email string) (*entity.User_data, error) {
@@ -104,13 +175,41 @@ func (t MahasiswaRepository) GetMahasiswaByNameAndEmail(name, email string) (*en
 	return &data, nil
 }
 
-func (t MahasiswaRepository) Update(mhsID, userID int64, updates map[string]interface{}) (*entity.User_data, error) {
-	var data entity.User_data
-	result := t.DB.Model(&data).Where("id = ? AND user_id = ?", mhsID, userID).Updates(updates)
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+// Update mengubah data yang bisa diubah user (pemilik atau editor), hasil nil tanpa error berarti
+// data tidak ditemukan
+func (t MahasiswaRepository) Update(mhsID, userID int64, updates map[string]interface{}, actor audit.Actor) (*entity.User_data, error) {
+	return t.update(mhsID, userID, updates, actor, entity.AuditActionUpdate)
+}
+
+// Revert sama dengan Update tetapi tercatat di history sebagai revert
+func (t MahasiswaRepository) Revert(mhsID, userID int64, updates map[string]interface{}, actor audit.Actor) (*entity.User_data, error) {
+	return t.update(mhsID, userID, updates, actor, entity.AuditActionRevert)
+}
+
+func (t MahasiswaRepository) update(mhsID, userID int64, updates map[string]interface{}, actor audit.Actor, action string) (*entity.User_data, error) {
+	var before, after entity.User_data
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", mhsID).
+			Scopes(accessScope(userID, entity.SharePermissionEditor)).
+			First(&before).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.User_data{}).Where("id = ?", mhsID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&after, mhsID).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, actor, recordEntry(action, &before, &after))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
-	return &data, result.Error
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
 }
 
 func (t MahasiswaRepository) UpdatetoAtch(mhs *entity.User_data) error {
@@ -118,21 +217,182 @@ func (t MahasiswaRepository) UpdatetoAtch(mhs *entity.User_data) error {
 	return err
 }
 
-func (t MahasiswaRepository) Delete(mhsID, userID int64) (int64, error) {
-	data := entity.User_data{}
+func (t MahasiswaRepository) Delete(mhsID, userID int64, actor audit.Actor) (int64, error) {
+	var rowsAffected int64
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		data := entity.User_data{}
 
-	// Fetch the data by ID and user_id
-	if err := t.DB.Where("id = ? AND user_id = ?", mhsID, userID).First(&data).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// If data not found, return 0 RowsAffected
-			return 0, nil
+		// Fetch the data by ID and user_id
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", mhsID, userID).First(&data).Error
+		if err != nil {
+			return err
+		}
+
+		// Delete the fetched data (soft delete, data pindah ke trash)
+		result := tx.Delete(&data)
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		return writeAudit(tx, actor, recordEntry(entity.AuditActionDelete, &data, nil))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// If data not found, return 0 RowsAffected
+		return 0, nil
+	}
+	return rowsAffected, err
+}
+
+func (t MahasiswaRepository) GetTrashByUser(userID int64, page pagination.Params) ([]entity.User_data, error) {
+	var data []entity.User_data
+
+	result := t.DB.Unscoped().Preload("Attachments", orderedAttachments).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Scopes(page.Scope).
+		Find(&data)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return data, nil
+}
+
+func (t MahasiswaRepository) GetTrashedByID(mhsID, userID int64) (*entity.User_data, error) {
+	var data entity.User_data
+	result := t.DB.Unscoped().Preload("Attachments", orderedAttachments).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", mhsID, userID).
+		First(&data)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &data, result.Error
+}
+
+func (t MahasiswaRepository) Restore(mhsID, userID int64, actor audit.Actor) (int64, error) {
+	var rowsAffected int64
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		var before, after entity.User_data
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", mhsID, userID).
+			First(&before).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Unscoped().Model(&entity.User_data{}).Where("id = ?", mhsID).Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		if err := tx.First(&after, mhsID).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, actor, recordEntry(entity.AuditActionRestore, &before, &after))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return rowsAffected, err
+}
+
+func (t MahasiswaRepository) Purge(mhsID, userID int64, actor audit.Actor) (*entity.User_data, error) {
+	var data []entity.User_data
+	err := t.DB.Unscoped().Preload("Attachments", orderedAttachments).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", mhsID, userID).
+		Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if _, err := t.purge(data, actor); err != nil {
+		return nil, err
+	}
+	return &data[0], nil
+}
+
+func (t MahasiswaRepository) PurgeDeletedBefore(before time.Time, actor audit.Actor) ([]entity.User_data, error) {
+	var data []entity.User_data
+	err := t.DB.Unscoped().Preload("Attachments", orderedAttachments).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := t.purge(data, actor); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// purge menghapus permanen data beserta lampiran dan comment-nya, file baru dihapus setelah transaksi berhasil.
+// Data dan setiap lampirannya tercatat di history sebagai purge
+func (t MahasiswaRepository) purge(data []entity.User_data, actor audit.Actor) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, 0, len(data))
+	var attachments []entity.Attachment
+	var entries []audit.Entry
+	for i, d := range data {
+		ids = append(ids, d.ID)
+		attachments = append(attachments, d.Attachments...)
+		for j := range d.Attachments {
+			entries = append(entries, attachmentEntry(entity.AuditActionPurge, d.ID, &d.Attachments[j], nil))
 		}
+		entries = append(entries, recordEntry(entity.AuditActionPurge, &data[i], nil))
+	}
+
+	var rowsAffected int64
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id IN ?", ids).Delete(&entity.Attachment{}).Error; err != nil {
+			return err
+		}
+		// comment ikut terhapus permanen bersama datanya
+		err := tx.Where("comment_id IN (SELECT id FROM comments WHERE record_id IN ?)", ids).
+			Delete(&entity.CommentRevision{}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("record_id IN ?", ids).Delete(&entity.Comment{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&entity.User_data{})
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		return writeAudit(tx, actor, entries...)
+	})
+	if err != nil {
 		return 0, err
 	}
 
-	// Delete the fetched data
-	result := t.DB.Delete(&data)
-	return result.RowsAffected, result.Error
+	// data sudah terhapus, file yang gagal dihapus cukup di log
+	for _, attachment := range attachments {
+		t.removeAttachmentFiles(context.Background(), attachment)
+	}
+
+	return rowsAffected, nil
+}
+
+// removeStoredFile menghapus file lampiran dari storage driver, file yang sudah tidak ada dianggap berhasil
+func (t MahasiswaRepository) removeStoredFile(ctx context.Context, driver, key string) error {
+	store, err := t.Storage.For(driver)
+	if err != nil {
+		return err
+	}
+	err = store.Delete(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	return err
 }
 func (t MahasiswaRepository) CreateAdmin(admin *entity.Admin) error {
 	result := t.DB.Create(admin)
@@ -161,189 +421,216 @@ func (t MahasiswaRepository) GetUserByUsernameOrEmail(username, email string) (*
 
 /////////////////////////////////////////
 
-func (t *MahasiswaRepository) CreateAttachment(mhsID int64, path string, order int64) (*entity.Attachment, error) {
+func (t *MahasiswaRepository) CreateAttachment(mhsID int64, path string, order int64, actor audit.Actor) (*entity.Attachment, error) {
 	attachment := &entity.Attachment{
 		UserID:          mhsID,
 		Path:            path,
+		Storage:         t.Storage.Default,
 		AttachmentOrder: order,
 	}
-	if err := t.DB.Create(attachment).Error; err != nil {
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attachment).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, actor, attachmentEntry(entity.AuditActionCreate, mhsID, nil, attachment))
+	})
+	if err != nil {
 		return nil, err
 	}
 	return attachment, nil
 }
 
-func (t *MahasiswaRepository) UploadFileS3Atch(file *multipart.FileHeader, mhsID, userID int64) (*entity.Attachment, error) {
-	//Mengambil data berdasarkan ID dan user_id
-
-	dataMhs := &entity.User_data{}
-	if err := t.DB.Where("id = ? AND user_id = ?", mhsID, userID).First(dataMhs).Error; err != nil {
+// SaveAttachment menyimpan file (beserta variant-nya) ke storage lalu mencatatnya sebagai lampiran terakhir,
+// file di storage dihapus lagi kalau pencatatan di database gagal
+func (t *MahasiswaRepository) SaveAttachment(ctx context.Context, mhsID, userID int64, file repository.AttachmentFile, actor audit.Actor) (*entity.Attachment, error) {
+	// pemilik atau editor
+	data := &entity.User_data{}
+	if err := t.DB.Where("id = ?", mhsID).Scopes(accessScope(userID, entity.SharePermissionEditor)).First(data).Error; err != nil {
 		return nil, err
 	}
 
-	src, err := file.Open()
+	attachment, err := t.storeAttachmentFile(ctx, file)
 	if err != nil {
-		logrus.Error(err)
 		return nil, err
 	}
-	defer src.Close()
-
-	// bikin nama file yang uniq untuk menghindari konflik
-	uniqueFilename := fmt.Sprintf("%s%s", uuid.NewString(), filepath.Ext(file.Filename))
-
-	// Upload the file to S3
-	bucketName := "bucketwithrey"
-	objectKey := uniqueFilename
-	_, err = t.S3Bucket.PutObject(context.TODO(), &s3.PutObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(objectKey),
-		Body:   src,
-		//ACL:    types.ObjectCannedACLPublicRead, // Optional: Mengatur ACL agar file yang diunggah dapat diakses oleh publik
+	attachment.UserID = mhsID
+	err = t.DB.Transaction(func(tx *gorm.DB) error {
+		if err := appendAttachment(tx, attachment); err != nil {
+			return err
+		}
+		return writeAudit(tx, actor, attachmentEntry(entity.AuditActionCreate, mhsID, nil, attachment))
 	})
 	if err != nil {
-		logrus.Error(err)
-		return nil, err
-	}
-
-	// Return the public URL of the uploaded file
-	publicURL := fmt.Sprintf("https://%s.s3.amazonaws.com/%s", bucketName, objectKey)
-
-	// Create an attachment record in the database
-	var attachmentOrder int64 = 1 // Set the initial attachment_order
-	// Get the count of existing attachments for the dataMhs
-	existingAttachmentCount := int64(0)
-	t.DB.Model(&entity.Attachment{}).Where("user_id = ?", mhsID).Count(&existingAttachmentCount)
-	attachmentOrder = existingAttachmentCount + 1 // Set attachment_order dynamically
-
-	// Create an attachment record in the database
-	attachment := &entity.Attachment{
-		UserID:          mhsID,
-		Path:            publicURL,
-		AttachmentOrder: attachmentOrder, // atur order
-		Timestamp:       time.Now(),
-	}
-	err = t.DB.Create(attachment).Error
-	if err != nil {
+		t.removeAttachmentFiles(context.Background(), *attachment)
 		return nil, err
 	}
-
 	return attachment, nil
 }
-func (t *MahasiswaRepository) UpdateWithAttachments(mhs *entity.User_data) error {
+
+// UpdateWithAttachments mengganti semua lampiran data dengan mhs.Attachments, urutannya mengikuti slice.
+// Lampiran lama tercatat di history sebagai delete dan lampiran baru sebagai create
+func (t *MahasiswaRepository) UpdateWithAttachments(mhs *entity.User_data, actor audit.Actor) error {
 	return t.DB.Transaction(func(tx *gorm.DB) error {
+		var old []entity.Attachment
+		if err := tx.Where("user_id = ?", mhs.ID).Find(&old).Error; err != nil {
+			return err
+		}
+		entries := make([]audit.Entry, 0, len(old)+len(mhs.Attachments))
+		for i := range old {
+			entries = append(entries, attachmentEntry(entity.AuditActionDelete, mhs.ID, &old[i], nil))
+		}
+
 		// Pertama, hapus semua lampiran yang ada yang terkait dengan data
-		if err := t.DB.Where("user_id = ?", mhs.ID).Delete(&entity.Attachment{}).Error; err != nil {
+		if err := tx.Where("user_id = ?", mhs.ID).Delete(&entity.Attachment{}).Error; err != nil {
 			return err
 		}
 
 		// Next, create new attachment records
 		for i := range mhs.Attachments {
 			attachment := &mhs.Attachments[i]
-			if err := t.DB.Create(attachment).Error; err != nil {
+			attachment.UserID = mhs.ID
+			attachment.AttachmentOrder = int64(i + 1)
+			if err := tx.Create(attachment).Error; err != nil {
 				return err
 			}
+			entries = append(entries, attachmentEntry(entity.AuditActionCreate, mhs.ID, nil, attachment))
 		}
 
-		return nil
+		return writeAudit(tx, actor, entries...)
 	})
 }
 
-func (t *MahasiswaRepository) UploadFileS3Buckets(file io.Reader, fileName string) (*string, error) {
-	bucketName := "bucketwithrey"
-	objectKey := fileName
+// SearchMahasiswaByUser paginasi dengan OFFSET untuk client lama yang masih mengirim page.
+//
+// Deprecated: pakai GetAllUserByID dengan pagination.Params (cursor), kedalaman page dibatasi handler
+func (t *MahasiswaRepository) SearchMahasiswaByUser(userID int64, filter request.MahasiswaFilter, sort []request.SortField, page, perPage int, proj request.Projection) ([]entity.User_data, int64, error) {
+	var dataMhs []entity.User_data
 
-	_, err := t.S3Bucket.PutObject(context.TODO(), &s3.PutObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(objectKey),
-		Body:   file,
-	})
+	// Menghitung total data
+	var total int64
+	err := t.DB.Model(&entity.User_data{}).Scopes(filterScope(userID, filter)).Count(&total).Error
 	if err != nil {
-		logrus.Error(err)
-		return nil, err
+		return nil, 0, err
 	}
 
-	// Return the public URL of the uploaded file
-	publicURL := aws.String(fmt.Sprintf("https://%s.s3.amazonaws.com/%s", bucketName, objectKey))
+	// Mengambil data dengan paginasi
+	offset := (page - 1) * perPage
+	err = t.DB.Scopes(filterScope(userID, filter), sortScope(sort), projectionScope(userID, proj)).
+		Offset(offset).Limit(perPage).
+		Find(&dataMhs).Error
 
-	return publicURL, nil
+	return dataMhs, total, err
 }
 
-func (t *MahasiswaRepository) UploadFileLocalAtch(file *multipart.FileHeader, mhsID, userID int64) (*entity.Attachment, error) {
-	// Fetch the data by ID and user_id
-	data := &entity.User_data{}
-	if err := t.DB.Where("id = ? AND user_id = ?", mhsID, userID).First(data).Error; err != nil {
-		return nil, err
-	}
-
-	src, err := file.Open()
+// StreamByUser membaca data satu per satu dari database lalu memanggil fn untuk setiap baris,
+// dipakai export supaya data tidak dimuat ke memory sekaligus
+func (t *MahasiswaRepository) StreamByUser(userID int64, filter request.MahasiswaFilter, columns []string, fn func(entity.User_data) error) error {
+	rows, err := t.DB.Model(&entity.User_data{}).
+		Scopes(filterScope(userID, filter)).
+		Select(columns).
+		Order("id").
+		Rows()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer src.Close()
-
-	// bikin nama file yang uniq untuk menghindari konflik
-	uniqueFilename := fmt.Sprintf("%s%s", uuid.NewString(), filepath.Ext(file.Filename))
+	defer rows.Close()
 
-	// Upload the file to Local
-	uploadDir := "uploads"
-
-	// Buat direktori unggahan jika belum ada
-	err = os.MkdirAll(uploadDir, 0755)
-	if err != nil {
-		return nil, err
+	for rows.Next() {
+		var data entity.User_data
+		if err := t.DB.ScanRows(rows, &data); err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
 	}
+	return rows.Err()
+}
 
-	// Create the destination file
-	dest, err := os.Create(filepath.Join(uploadDir, uniqueFilename))
+// StreamAll membaca semua data mahasiswa yang belum dihapus, dipakai untuk membangun index pencarian
+func (t *MahasiswaRepository) StreamAll(fn func(entity.User_data) error) error {
+	rows, err := t.DB.Model(&entity.User_data{}).Order("id").Rows()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer dest.Close()
+	defer rows.Close()
 
-	// Copy file nya ke file tujuan
-	_, err = io.Copy(dest, src)
-	if err != nil {
-		return nil, err
+	for rows.Next() {
+		var data entity.User_data
+		if err := t.DB.ScanRows(rows, &data); err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
 	}
-	// Return the local file path
-	localFilePath := filepath.Join(uploadDir, uniqueFilename)
-
-	// Create an attachment record in the database
-	var attachmentOrder int64 = 1 // Set the initial attachment_order
-	// Get the count of existing attachments for the dataMhs
-	existingAttachmentCount := int64(0)
-	t.DB.Model(&entity.Attachment{}).Where("user_id = ?", mhsID).Count(&existingAttachmentCount)
-	attachmentOrder = existingAttachmentCount + 1 // Set attachment_order dynamically
+	return rows.Err()
+}
 
-	// Create an attachment record in the database
-	attachment := &entity.Attachment{
-		UserID:          mhsID,
-		Path:            localFilePath,
-		AttachmentOrder: attachmentOrder, // atur order
-		Timestamp:       time.Now(),
+// filterScope filter data milik user sesuai parameter search
+func filterScope(userID int64, filter request.MahasiswaFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ?", userID).Scopes(criteriaScope(userID, filter))
 	}
-	err = t.DB.Create(attachment).Error
-	if err != nil {
-		return nil, err
-	}
-
-	return attachment, nil
 }
 
-func (t *MahasiswaRepository) SearchMahasiswaByUser(userID int64, search string, page, perPage int) ([]entity.User_data, int64, error) {
-	var dataMhs []entity.User_data
-
-	// Menghitung total data
-	var total int64
-	t.DB.Model(&entity.User_data{}).Where("user_id = ? AND title LIKE ?", userID, "%"+search+"%").Count(&total)
+// criteriaScope kondisi parameter search tanpa membatasi pemilik data,
+// userID hanya dipakai untuk menentukan tag mana yang terlihat
+func criteriaScope(userID int64, filter request.MahasiswaFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Search != "" {
+			like := "%" + rsql.EscapeLike(filter.Search) + "%"
+			db = db.Where(`(name LIKE ? ESCAPE '\\' OR email LIKE ? ESCAPE '\\' OR address LIKE ? ESCAPE '\\' `+
+				`OR phone_number LIKE ? ESCAPE '\\')`, like, like, like, like)
+		}
+		if filter.AgeMin != nil {
+			db = db.Where(entity.AgeSQL+" >= ?", *filter.AgeMin)
+		}
+		if filter.AgeMax != nil {
+			db = db.Where(entity.AgeSQL+" <= ?", *filter.AgeMax)
+		}
+		if filter.BirthFrom != "" {
+			db = db.Where("birthdate >= ?", filter.BirthFrom)
+		}
+		if filter.BirthTo != "" {
+			db = db.Where("birthdate <= ?", filter.BirthTo)
+		}
+		if filter.Where != nil {
+			db = db.Where(filter.Where)
+		}
+		return db.Scopes(tagFilterScope(userID, filter))
+	}
+}
 
-	// Mengambil data dengan paginasi
-	offset := (page - 1) * perPage
-	err := t.DB.Where("user_id = ? AND title LIKE ?", userID, "%"+search+"%").
-		Offset(offset).Limit(perPage).
-		Preload("Attachments").Find(&dataMhs).Error
+// sortScope mengurutkan data, kolom sudah divalidasi di handler dan id selalu jadi urutan terakhir
+func sortScope(sort []request.SortField) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, field := range sort {
+			column := clause.Column{Name: field.Column}
+			if field.Column == "age" {
+				column = clause.Column{Name: entity.AgeSQL, Raw: true}
+			}
+			db = db.Order(clause.OrderByColumn{Column: column, Desc: field.Desc})
+		}
+		return db.Order("id")
+	}
+}
 
-	return dataMhs, total, err
+// projectionScope memilih kolom (sudah divalidasi di handler) dan hanya memuat attachments kalau diminta.
+// Tag yang dimuat hanya tag yang terlihat oleh viewerID, supaya tag pribadi pemilik tidak bocor ke data yang di-share
+func projectionScope(viewerID int64, proj request.Projection) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(proj.Fields) > 0 {
+			db = db.Select(proj.Fields)
+		}
+		if proj.Attachments {
+			db = db.Preload("Attachments", orderedAttachments)
+		}
+		if proj.Tags {
+			db = db.Preload("Tags", visibleTagScope(viewerID))
+		}
+		return db
+	}
 }
 
 // //////////////////////////////////////////////////////
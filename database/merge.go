@@ -0,0 +1,164 @@
+package database
+
+import (
+	"errors"
+	"ginDatabaseMhs/audit"
+	"ginDatabaseMhs/model/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MergeMahasiswa menggabungkan duplicate ke survivor dalam satu transaksi: lampiran duplicate dipindah
+// ke survivor (urutan diletakkan setelah lampiran survivor), comment, share, enrollment beserta nilai dan
+// absensi dipindah, tag duplicate ikut dipasang ke survivor, duplicate dihapus permanen lalu updates
+// diterapkan ke survivor. Duplicate, survivor dan lampiran yang dipindah tercatat di history sebagai merge.
+// Hasil nil tanpa error berarti salah satu data tidak ditemukan
+func (t MahasiswaRepository) MergeMahasiswa(survivorID, duplicateID, userID int64, updates map[string]interface{}, actor audit.Actor) (*entity.User_data, error) {
+	var survivor entity.User_data
+	err := t.DB.Transaction(func(tx *gorm.DB) error {
+		var rows []entity.User_data
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND user_id = ?", []int64{survivorID, duplicateID}, userID).
+			Find(&rows).Error
+		if err != nil {
+			return err
+		}
+		if len(rows) != 2 {
+			return gorm.ErrRecordNotFound
+		}
+		before, duplicate := rows[0], rows[1]
+		if before.ID != survivorID {
+			before, duplicate = duplicate, before
+		}
+		var moved []entity.Attachment
+		if err := tx.Where("user_id = ?", duplicateID).Find(&moved).Error; err != nil {
+			return err
+		}
+
+		var maxOrder int64
+		err = tx.Model(&entity.Attachment{}).
+			Where("user_id = ?", survivorID).
+			Select("COALESCE(MAX(attachment_order), 0)").
+			Scan(&maxOrder).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&entity.Attachment{}).
+			Where("user_id = ?", duplicateID).
+			Updates(map[string]interface{}{
+				"user_id":          survivorID,
+				"attachment_order": gorm.Expr("attachment_order + ?", maxOrder),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&entity.Comment{}).Where("record_id = ?", duplicateID).Update("record_id", survivorID).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(
+			"INSERT IGNORE INTO record_tags (record_id, tag_id, created_at) SELECT ?, tag_id, created_at FROM record_tags WHERE record_id = ?",
+			survivorID, duplicateID,
+		).Error
+		if err != nil {
+			return err
+		}
+
+		if err := moveRecordChildren(tx, survivorID, duplicateID); err != nil {
+			return err
+		}
+
+		// hapus permanen dulu supaya nilai name/email dari duplicate bisa dipakai survivor tanpa bentrok unique index
+		if err := tx.Unscoped().Delete(&entity.User_data{}, duplicateID).Error; err != nil {
+			return err
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&entity.User_data{}).Where("id = ?", survivorID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Preload("Attachments", orderedAttachments).First(&survivor, survivorID).Error; err != nil {
+			return err
+		}
+
+		// entry duplicate masuk ke history survivor supaya data duplicate tetap bisa dilihat
+		mergedEntry := recordEntry(entity.AuditActionMerge, &duplicate, nil)
+		mergedEntry.RecordID = survivorID
+		entries := []audit.Entry{mergedEntry, recordEntry(entity.AuditActionMerge, &before, &survivor)}
+		for i := range moved {
+			for j := range survivor.Attachments {
+				if survivor.Attachments[j].ID == moved[i].ID {
+					entries = append(entries, attachmentEntry(entity.AuditActionMerge, survivorID, &moved[i], &survivor.Attachments[j]))
+				}
+			}
+		}
+		return writeAudit(tx, actor, entries...)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &survivor, nil
+}
+
+// moveRecordChildren memindahkan share, enrollment (nilai ikut karena terhubung lewat enrollment) dan absensi
+// duplicate ke survivor sebelum duplicate dihapus. Kalau bentrok dengan unique index, baris survivor yang
+// dipertahankan: share mengambil permission tertinggi, nilai duplicate dipindah ke enrollment survivor yang
+// belum punya nilai
+func moveRecordChildren(tx *gorm.DB, survivorID, duplicateID int64) error {
+	statements := []struct {
+		sql  string
+		args []interface{}
+	}{
+		{
+			"UPDATE record_shares s JOIN record_shares d ON d.user_id = s.user_id AND d.record_id = ? " +
+				"SET s.permission = ? WHERE s.record_id = ? AND d.permission = ?",
+			[]interface{}{duplicateID, entity.SharePermissionEditor, survivorID, entity.SharePermissionEditor},
+		},
+		{
+			"DELETE d FROM record_shares d JOIN record_shares s ON s.user_id = d.user_id AND s.record_id = ? " +
+				"WHERE d.record_id = ?",
+			[]interface{}{survivorID, duplicateID},
+		},
+		{
+			"UPDATE record_shares SET record_id = ? WHERE record_id = ?",
+			[]interface{}{survivorID, duplicateID},
+		},
+		{
+			"UPDATE grades g JOIN enrollments d ON d.id = g.enrollment_id " +
+				"JOIN enrollments s ON s.record_id = ? AND s.course_id = d.course_id AND s.semester_id = d.semester_id " +
+				"LEFT JOIN grades sg ON sg.enrollment_id = s.id " +
+				"SET g.enrollment_id = s.id WHERE d.record_id = ? AND sg.id IS NULL",
+			[]interface{}{survivorID, duplicateID},
+		},
+		{
+			"DELETE d FROM enrollments d JOIN enrollments s " +
+				"ON s.record_id = ? AND s.course_id = d.course_id AND s.semester_id = d.semester_id " +
+				"WHERE d.record_id = ?",
+			[]interface{}{survivorID, duplicateID},
+		},
+		{
+			"UPDATE enrollments SET record_id = ? WHERE record_id = ?",
+			[]interface{}{survivorID, duplicateID},
+		},
+		{
+			"DELETE d FROM attendances d JOIN attendances s ON s.session_id = d.session_id AND s.record_id = ? " +
+				"WHERE d.record_id = ?",
+			[]interface{}{survivorID, duplicateID},
+		},
+		{
+			"UPDATE attendances SET record_id = ? WHERE record_id = ?",
+			[]interface{}{survivorID, duplicateID},
+		},
+	}
+	for _, stmt := range statements {
+		if err := tx.Exec(stmt.sql, stmt.args...).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,3 @@
+ALTER TABLE user_data
+    DROP INDEX idx_user_data_deleted_at,
+    DROP COLUMN deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE user_data
+    ADD COLUMN deleted_at DATETIME(3) NULL,
+    ADD INDEX idx_user_data_deleted_at (deleted_at);
@@ -0,0 +1,17 @@
+CREATE TABLE audit_logs
+(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id BIGINT NOT NULL,
+    record_id BIGINT NOT NULL,
+    version INT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    actor_id BIGINT NOT NULL DEFAULT 0,
+    request_id VARCHAR(64),
+    changes JSON,
+    snapshot JSON,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_audit_entity_version (entity_type, entity_id, version),
+    INDEX idx_audit_record (record_id)
+);
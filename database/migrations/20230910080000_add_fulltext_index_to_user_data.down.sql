@@ -0,0 +1 @@
+ALTER TABLE user_data DROP INDEX idx_user_data_fulltext;
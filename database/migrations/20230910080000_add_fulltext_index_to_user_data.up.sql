@@ -0,0 +1 @@
+ALTER TABLE user_data ADD FULLTEXT INDEX idx_user_data_fulltext (name, email, address, phone_number);
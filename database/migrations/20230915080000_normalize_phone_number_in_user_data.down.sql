@@ -0,0 +1,2 @@
+-- format nomor telepon sebelum normalisasi tidak disimpan, tidak ada yang dikembalikan
+DO 0;
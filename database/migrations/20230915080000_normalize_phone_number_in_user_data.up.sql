@@ -0,0 +1,13 @@
+UPDATE user_data
+SET phone_number = CASE
+    WHEN REGEXP_REPLACE(phone_number, '[^0-9+]', '') REGEXP '^\\+[1-9][0-9]{7,14}$'
+        THEN REGEXP_REPLACE(phone_number, '[^0-9+]', '')
+    WHEN REGEXP_REPLACE(phone_number, '[^0-9]', '') REGEXP '^62[1-9][0-9]{6,11}$'
+        THEN CONCAT('+', REGEXP_REPLACE(phone_number, '[^0-9]', ''))
+    WHEN REGEXP_REPLACE(phone_number, '[^0-9]', '') REGEXP '^0[1-9][0-9]{6,11}$'
+        THEN CONCAT('+62', SUBSTRING(REGEXP_REPLACE(phone_number, '[^0-9]', ''), 2))
+    WHEN REGEXP_REPLACE(phone_number, '[^0-9]', '') REGEXP '^8[0-9]{8,11}$'
+        THEN CONCAT('+62', REGEXP_REPLACE(phone_number, '[^0-9]', ''))
+    ELSE phone_number
+END
+WHERE phone_number IS NOT NULL AND phone_number <> '';
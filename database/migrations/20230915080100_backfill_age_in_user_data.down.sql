@@ -0,0 +1,2 @@
+-- age yang diisi manual sebelum backfill tidak disimpan, tidak ada yang dikembalikan
+DO 0;
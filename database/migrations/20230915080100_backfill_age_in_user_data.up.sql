@@ -0,0 +1,3 @@
+UPDATE user_data
+SET age = TIMESTAMPDIFF(YEAR, birthdate, CURDATE())
+WHERE birthdate IS NOT NULL;
@@ -0,0 +1,15 @@
+CREATE TABLE record_shares
+(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    record_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    permission VARCHAR(16) NOT NULL,
+    granted_by BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_record_share_user (record_id, user_id),
+    INDEX idx_record_share_grantee (user_id),
+    FOREIGN KEY (record_id) REFERENCES user_data(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+CREATE TABLE enrollments
+(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    record_id BIGINT NOT NULL,
+    course_id BIGINT NOT NULL,
+    semester_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_enrollment (record_id, course_id, semester_id),
+    FOREIGN KEY (record_id) REFERENCES user_data(id) ON DELETE CASCADE,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE RESTRICT,
+    FOREIGN KEY (semester_id) REFERENCES semesters(id) ON DELETE RESTRICT
+);
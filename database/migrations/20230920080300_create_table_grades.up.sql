@@ -0,0 +1,12 @@
+CREATE TABLE grades
+(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    enrollment_id BIGINT NOT NULL,
+    letter VARCHAR(4) NOT NULL,
+    score DECIMAL(5,2),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_grade_enrollment (enrollment_id),
+    FOREIGN KEY (enrollment_id) REFERENCES enrollments(id) ON DELETE CASCADE
+);
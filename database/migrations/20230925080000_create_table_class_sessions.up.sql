@@ -0,0 +1,18 @@
+CREATE TABLE class_sessions
+(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT NOT NULL,
+    course_id BIGINT NOT NULL,
+    semester_id BIGINT NOT NULL,
+    meeting_number INT NOT NULL,
+    date DATE NOT NULL,
+    topic VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_class_session_meeting (course_id, semester_id, meeting_number),
+    INDEX idx_class_session_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE RESTRICT,
+    FOREIGN KEY (semester_id) REFERENCES semesters(id) ON DELETE RESTRICT
+);
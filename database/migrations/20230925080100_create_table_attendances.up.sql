@@ -0,0 +1,15 @@
+CREATE TABLE attendances
+(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    session_id BIGINT NOT NULL,
+    record_id BIGINT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    note VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_attendance_record (session_id, record_id),
+    INDEX idx_attendance_student (record_id),
+    FOREIGN KEY (session_id) REFERENCES class_sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (record_id) REFERENCES user_data(id) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+CREATE TABLE record_tags
+(
+    record_id BIGINT NOT NULL,
+    tag_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (record_id, tag_id),
+    INDEX idx_record_tag_tag (tag_id),
+    FOREIGN KEY (record_id) REFERENCES user_data(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
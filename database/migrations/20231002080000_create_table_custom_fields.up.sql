@@ -0,0 +1,16 @@
+CREATE TABLE custom_fields
+(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT NOT NULL,
+    `key` VARCHAR(50) NOT NULL,
+    label VARCHAR(100) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    options JSON NULL,
+    pattern VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_custom_field_key (user_id, `key`),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
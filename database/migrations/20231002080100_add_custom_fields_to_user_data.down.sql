@@ -0,0 +1,2 @@
+ALTER TABLE user_data
+    DROP COLUMN custom_fields;
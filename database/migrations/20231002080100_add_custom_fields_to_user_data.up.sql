@@ -0,0 +1,2 @@
+ALTER TABLE user_data
+    ADD COLUMN custom_fields JSON NULL;
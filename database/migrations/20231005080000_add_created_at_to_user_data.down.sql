@@ -0,0 +1,3 @@
+ALTER TABLE user_data
+    DROP INDEX idx_user_data_created_at,
+    DROP COLUMN created_at;
@@ -0,0 +1,3 @@
+ALTER TABLE user_data
+    ADD COLUMN created_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD INDEX idx_user_data_created_at (created_at);
@@ -0,0 +1,2 @@
+-- created_at kolomnya dihapus di migrasi sebelumnya, tidak ada yang dikembalikan
+DO 0;
@@ -0,0 +1,9 @@
+-- waktu dibuat diambil dari history create, data tanpa history tetap memakai waktu migrasi
+UPDATE user_data
+    JOIN (
+        SELECT record_id, MIN(created_at) AS created_at
+        FROM audit_logs
+        WHERE entity_type = 'user_data' AND action = 'create'
+        GROUP BY record_id
+    ) created ON created.record_id = user_data.id
+SET user_data.created_at = created.created_at;
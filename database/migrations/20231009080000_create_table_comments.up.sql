@@ -0,0 +1,15 @@
+CREATE TABLE comments
+(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    record_id BIGINT NOT NULL,
+    author_id BIGINT NOT NULL,
+    body TEXT NOT NULL,
+    visibility VARCHAR(16) NOT NULL DEFAULT 'shared',
+    edit_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_comment_record (record_id),
+    FOREIGN KEY (record_id) REFERENCES user_data(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
+);
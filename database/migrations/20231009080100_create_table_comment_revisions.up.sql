@@ -0,0 +1,12 @@
+CREATE TABLE comment_revisions
+(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    comment_id BIGINT NOT NULL,
+    body TEXT NOT NULL,
+    visibility VARCHAR(16) NOT NULL,
+    edited_by BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_comment_revision_comment (comment_id),
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
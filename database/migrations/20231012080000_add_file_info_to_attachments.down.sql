@@ -0,0 +1,4 @@
+ALTER TABLE attachments
+    DROP COLUMN file_name,
+    DROP COLUMN content_type,
+    DROP COLUMN size;
@@ -0,0 +1,4 @@
+ALTER TABLE attachments
+    ADD COLUMN file_name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN content_type VARCHAR(127) NOT NULL DEFAULT '',
+    ADD COLUMN size BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE attachments DROP COLUMN storage;
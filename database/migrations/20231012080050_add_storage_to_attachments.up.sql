@@ -0,0 +1 @@
+ALTER TABLE attachments ADD COLUMN storage VARCHAR(16) NOT NULL DEFAULT '' AFTER path;
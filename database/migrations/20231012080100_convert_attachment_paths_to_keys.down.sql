@@ -0,0 +1,2 @@
+-- URL S3 lama tidak bisa dibentuk ulang dari key, path tidak dikembalikan
+DO 0;
@@ -0,0 +1,13 @@
+-- path lama berupa URL S3 atau "uploads/<file>", sekarang hanya key di storage dan driver-nya dicatat
+-- di kolom storage. Path lain dibiarkan dengan storage kosong yang berarti driver default
+UPDATE attachments
+SET storage = CASE
+        WHEN path LIKE 'https://%' THEN 's3'
+        WHEN path LIKE 'uploads/%' THEN 'local'
+        ELSE storage
+    END,
+    path = CASE
+        WHEN path LIKE 'https://%' THEN SUBSTRING_INDEX(path, '/', -1)
+        WHEN path LIKE 'uploads/%' THEN SUBSTRING(path, 9)
+        ELSE path
+    END;
@@ -0,0 +1,16 @@
+CREATE TABLE pending_uploads
+(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    `key` VARCHAR(255) NOT NULL,
+    record_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(127) NOT NULL,
+    size BIGINT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_pending_upload_key (`key`),
+    FOREIGN KEY (record_id) REFERENCES user_data(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
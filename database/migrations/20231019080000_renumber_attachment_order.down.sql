@@ -0,0 +1,2 @@
+-- urutan lama tidak disimpan, tidak ada yang dikembalikan
+DO 0;
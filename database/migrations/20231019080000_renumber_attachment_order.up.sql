@@ -0,0 +1,7 @@
+-- urutan lama dihitung dari jumlah lampiran sehingga bisa bolong atau dobel, dirapikan jadi 1..n per data
+UPDATE attachments
+    JOIN (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY attachment_order, id) AS position
+        FROM attachments
+    ) ordered ON ordered.id = attachments.id
+SET attachments.attachment_order = ordered.position;
@@ -0,0 +1,2 @@
+ALTER TABLE attachments
+    DROP INDEX idx_attachment_order;
@@ -0,0 +1,2 @@
+ALTER TABLE attachments
+    ADD UNIQUE KEY idx_attachment_order (user_id, attachment_order);
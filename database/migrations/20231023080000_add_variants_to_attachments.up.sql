@@ -0,0 +1,2 @@
+ALTER TABLE attachments
+    ADD COLUMN variants JSON NULL;
@@ -0,0 +1 @@
+DROP INDEX idx_pending_upload_expires ON pending_uploads;
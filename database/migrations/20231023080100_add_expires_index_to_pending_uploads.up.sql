@@ -0,0 +1 @@
+CREATE INDEX idx_pending_upload_expires ON pending_uploads (expires_at);
@@ -0,0 +1,5 @@
+ALTER TABLE tags
+    ADD UNIQUE KEY idx_tag_name (user_id, name),
+    DROP INDEX idx_tag_owner_name,
+    DROP INDEX idx_tag_user,
+    DROP COLUMN owner_key;
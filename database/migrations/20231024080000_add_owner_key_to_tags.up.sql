@@ -0,0 +1,5 @@
+ALTER TABLE tags
+    ADD COLUMN owner_key BIGINT AS (COALESCE(user_id, 0)) STORED AFTER user_id,
+    ADD INDEX idx_tag_user (user_id),
+    ADD UNIQUE KEY idx_tag_owner_name (owner_key, name),
+    DROP INDEX idx_tag_name;
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"errors"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// accessScope data milik userID atau yang dibagikan ke userID dengan salah satu permission
+func accessScope(userID int64, permissions ...string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"(user_id = ? OR id IN (SELECT record_id FROM record_shares WHERE user_id = ? AND permission IN ?))",
+			userID, userID, permissions,
+		)
+	}
+}
+
+// SaveShare memberi akses, kalau user sudah punya akses permission-nya diganti
+func (t MahasiswaRepository) SaveShare(share *entity.RecordShare) error {
+	return t.DB.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"permission", "granted_by", "updated_at"}),
+	}).Create(share).Error
+}
+
+func (t MahasiswaRepository) DeleteShare(recordID, userID int64) (int64, error) {
+	result := t.DB.Where("record_id = ? AND user_id = ?", recordID, userID).Delete(&entity.RecordShare{})
+	return result.RowsAffected, result.Error
+}
+
+func (t MahasiswaRepository) GetShare(recordID, userID int64) (*entity.RecordShare, error) {
+	var share entity.RecordShare
+	result := t.DB.Where("record_id = ? AND user_id = ?", recordID, userID).First(&share)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &share, result.Error
+}
+
+func (t MahasiswaRepository) GetSharesByRecord(recordID int64) ([]entity.RecordShare, error) {
+	var shares []entity.RecordShare
+	if err := t.DB.Where("record_id = ?", recordID).Order("id").Find(&shares).Error; err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// GetSharedWithUser data milik user lain yang dibagikan ke userID, data yang ada di trash tidak ikut
+func (t MahasiswaRepository) GetSharedWithUser(userID int64, page pagination.Params) ([]entity.RecordShare, error) {
+	var shares []entity.RecordShare
+	err := t.DB.Preload("Record").
+		Where("user_id = ?", userID).
+		Where("record_id IN (SELECT id FROM user_data WHERE deleted_at IS NULL)").
+		Scopes(page.Scope).
+		Find(&shares).Error
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
@@ -0,0 +1,21 @@
+package database
+
+import (
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+)
+
+// CountGroupedByUser jumlah data milik user per nilai expr, expr adalah ekspresi SQL dari kode, bukan dari input user
+func (t MahasiswaRepository) CountGroupedByUser(userID int64, filter request.MahasiswaFilter, expr string) ([]entity.GroupCount, error) {
+	var counts []entity.GroupCount
+	err := t.DB.Model(&entity.User_data{}).
+		Scopes(filterScope(userID, filter)).
+		Select("COALESCE(CAST(" + expr + " AS CHAR), '') AS `key`, COUNT(*) AS count").
+		Group("`key`").
+		Order("`key`").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"errors"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// visibleTagScope tag milik userID ditambah tag global
+func visibleTagScope(userID int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(user_id = ? OR user_id IS NULL)", userID)
+	}
+}
+
+// tagFilterScope data yang punya salah satu (atau semua kalau TagsAll) tag yang namanya ada di filter.Tags
+func tagFilterScope(userID int64, filter request.MahasiswaFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(filter.Tags) == 0 {
+			return db
+		}
+		sub := db.Session(&gorm.Session{NewDB: true}).
+			Table("record_tags").
+			Select("record_tags.record_id").
+			Joins("JOIN tags ON tags.id = record_tags.tag_id").
+			Where("tags.name IN ? AND (tags.user_id = ? OR tags.user_id IS NULL)", filter.Tags, userID).
+			Group("record_tags.record_id")
+		if filter.TagsAll {
+			sub = sub.Having("COUNT(DISTINCT tags.name) = ?", len(filter.Tags))
+		}
+		return db.Where("id IN (?)", sub)
+	}
+}
+
+func (t MahasiswaRepository) CreateTag(tag *entity.Tag) error {
+	return t.DB.Create(tag).Error
+}
+
+func (t MahasiswaRepository) GetTagsByUser(userID int64, page pagination.Params) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	if err := t.DB.Scopes(visibleTagScope(userID), page.Scope).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (t MahasiswaRepository) GetTagByID(tagID, userID int64) (*entity.Tag, error) {
+	var tag entity.Tag
+	result := t.DB.Scopes(visibleTagScope(userID)).Where("id = ?", tagID).First(&tag)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &tag, result.Error
+}
+
+// GetTagByName userID nil mencari di tag global
+func (t MahasiswaRepository) GetTagByName(userID *int64, name string) (*entity.Tag, error) {
+	var tag entity.Tag
+	db := t.DB.Where("name = ?", name)
+	if userID == nil {
+		db = db.Where("user_id IS NULL")
+	} else {
+		db = db.Where("user_id = ?", *userID)
+	}
+	result := db.First(&tag)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &tag, result.Error
+}
+
+func (t MahasiswaRepository) GetTagsByIDs(ids []int64, userID int64) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	if err := t.DB.Scopes(visibleTagScope(userID)).Where("id IN ?", ids).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (t MahasiswaRepository) UpdateTag(tagID int64, updates map[string]interface{}) (int64, error) {
+	result := t.DB.Model(&entity.Tag{}).Where("id = ?", tagID).Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteTag relasi ke data mahasiswa ikut terhapus lewat foreign key
+func (t MahasiswaRepository) DeleteTag(tagID int64) (int64, error) {
+	result := t.DB.Delete(&entity.Tag{}, tagID)
+	return result.RowsAffected, result.Error
+}
+
+// GetEditableRecordIDs id dari ids yang boleh diubah userID (pemilik atau editor)
+func (t MahasiswaRepository) GetEditableRecordIDs(ids []int64, userID int64) ([]int64, error) {
+	var editable []int64
+	err := t.DB.Model(&entity.User_data{}).
+		Scopes(accessScope(userID, entity.SharePermissionEditor)).
+		Where("id IN ?", ids).
+		Pluck("id", &editable).Error
+	if err != nil {
+		return nil, err
+	}
+	return editable, nil
+}
+
+// AddRecordTags memasang semua tag ke semua data, pasangan yang sudah ada diabaikan
+func (t MahasiswaRepository) AddRecordTags(recordIDs, tagIDs []int64) (int64, error) {
+	links := make([]entity.RecordTag, 0, len(recordIDs)*len(tagIDs))
+	for _, recordID := range recordIDs {
+		for _, tagID := range tagIDs {
+			links = append(links, entity.RecordTag{RecordID: recordID, TagID: tagID})
+		}
+	}
+	result := t.DB.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(links, 500)
+	return result.RowsAffected, result.Error
+}
+
+func (t MahasiswaRepository) RemoveRecordTags(recordIDs, tagIDs []int64) (int64, error) {
+	result := t.DB.Where("record_id IN ? AND tag_id IN ?", recordIDs, tagIDs).Delete(&entity.RecordTag{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,211 @@
+package dedupe
+
+import (
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/search"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultThreshold skor minimal supaya dua data dianggap kemungkinan duplikat
+const DefaultThreshold = 0.75
+
+// MaxBlockSize batas jumlah data dalam satu block. Block yang lebih besar (contoh awalan nama yang sangat umum)
+// dilewati supaya jumlah perbandingan tidak tumbuh kuadratik, pasangannya masih bisa ditemukan lewat key lain
+const MaxBlockSize = 100
+
+// bobot setiap field, field yang kosong di salah satu data tidak ikut dihitung
+var fieldWeights = []struct {
+	name   string
+	weight float64
+	score  func(a, b entity.User_data) (float64, bool)
+}{
+	{"name", 0.4, func(a, b entity.User_data) (float64, bool) {
+		return search.TextSimilarity(a.Name, b.Name), a.Name != "" && b.Name != ""
+	}},
+	{"email", 0.25, func(a, b entity.User_data) (float64, bool) {
+		ea, eb := NormalizeEmail(a.Email), NormalizeEmail(b.Email)
+		return emailSimilarity(ea, eb), ea != "" && eb != ""
+	}},
+	{"phone_number", 0.2, func(a, b entity.User_data) (float64, bool) {
+		pa, pb := NormalizePhone(a.PhoneNumber), NormalizePhone(b.PhoneNumber)
+		return phoneSimilarity(pa, pb), pa != "" && pb != ""
+	}},
+	{"birthdate", 0.15, func(a, b entity.User_data) (float64, bool) {
+		da, db := a.Birthdate.String(), b.Birthdate.String()
+		return dateSimilarity(da, db), da != "" && db != ""
+	}},
+}
+
+// Pair dua data yang kemungkinan duplikat beserta skor per field
+type Pair struct {
+	A      entity.User_data   `json:"a"`
+	B      entity.User_data   `json:"b"`
+	Score  float64            `json:"score"`
+	Fields map[string]float64 `json:"fields"`
+}
+
+// Score menghitung kemiripan dua data, hasilnya 0..1
+func Score(a, b entity.User_data) (float64, map[string]float64) {
+	fields := map[string]float64{}
+	var total, weights float64
+	for _, f := range fieldWeights {
+		s, ok := f.score(a, b)
+		if !ok {
+			continue
+		}
+		fields[f.name] = s
+		total += s * f.weight
+		weights += f.weight
+	}
+	// butuh minimal dua field untuk dibandingkan supaya nama yang sama saja tidak dianggap duplikat
+	if len(fields) < 2 || weights == 0 {
+		return 0, fields
+	}
+	return total / weights, fields
+}
+
+// FindPairs mencari pasangan duplikat, hanya data yang punya blocking key sama yang dibandingkan
+// supaya tidak perlu membandingkan semua kombinasi. Block dengan lebih dari MaxBlockSize data dilewati
+func FindPairs(records []entity.User_data, threshold float64) []Pair {
+	blocks := map[string][]int{}
+	for i, r := range records {
+		for _, key := range blockingKeys(r) {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	type pairKey struct{ a, b int }
+	seen := map[pairKey]bool{}
+	var pairs []Pair
+	for _, members := range blocks {
+		if len(members) > MaxBlockSize {
+			continue
+		}
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				k := pairKey{members[x], members[y]}
+				if k.a > k.b {
+					k = pairKey{k.b, k.a}
+				}
+				if k.a == k.b || seen[k] {
+					continue
+				}
+				seen[k] = true
+
+				s, fields := Score(records[k.a], records[k.b])
+				if s >= threshold {
+					pairs = append(pairs, Pair{A: records[k.a], B: records[k.b], Score: s, Fields: fields})
+				}
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Score == pairs[j].Score {
+			if pairs[i].A.ID == pairs[j].A.ID {
+				return pairs[i].B.ID < pairs[j].B.ID
+			}
+			return pairs[i].A.ID < pairs[j].A.ID
+		}
+		return pairs[i].Score > pairs[j].Score
+	})
+	return pairs
+}
+
+// blockingKeys kata nama yang sudah dinormalisasi (3 huruf awal), email, nomor telepon dan tanggal lahir
+func blockingKeys(r entity.User_data) []string {
+	var keys []string
+	for _, term := range search.QueryTokens(r.Name) {
+		if len(term) >= 3 {
+			keys = append(keys, "n:"+term[:3])
+		}
+	}
+	if email := NormalizeEmail(r.Email); email != "" {
+		keys = append(keys, "e:"+email)
+	}
+	if phone := NormalizePhone(r.PhoneNumber); len(phone) >= 8 {
+		keys = append(keys, "p:"+phone[len(phone)-8:])
+	}
+	if date := r.Birthdate.String(); date != "" {
+		keys = append(keys, "d:"+date)
+	}
+	return keys
+}
+
+// NormalizeEmail huruf kecil, tanpa +tag dan untuk gmail titik di nama user diabaikan
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+	return local + "@" + domain
+}
+
+// NormalizePhone hanya angka dengan kode negara 62, contoh "0812-3456" menjadi "628123456"
+func NormalizePhone(phone string) string {
+	var sb strings.Builder
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	digits := sb.String()
+	if strings.HasPrefix(digits, "0") {
+		digits = "62" + digits[1:]
+	}
+	return digits
+}
+
+func emailSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	localA, domainA := splitEmail(a)
+	localB, domainB := splitEmail(b)
+	s := search.TextSimilarity(localA, localB)
+	if domainA != domainB {
+		s *= 0.8
+	}
+	return s
+}
+
+func splitEmail(email string) (string, string) {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email, ""
+	}
+	return email[:at], email[at+1:]
+}
+
+// nomor telepon dianggap sama kalau 8 digit terakhirnya sama
+func phoneSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if len(a) >= 8 && len(b) >= 8 && a[len(a)-8:] == b[len(b)-8:] {
+		return 0.9
+	}
+	return 0
+}
+
+// tanggal lahir yang hanya tertukar hari dan bulannya dianggap mirip
+func dateSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if len(a) == 10 && len(b) == 10 && a[:4] == b[:4] && a[5:7] == b[8:10] && a[8:10] == b[5:7] {
+		return 0.5
+	}
+	return 0
+}
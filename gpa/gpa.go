@@ -0,0 +1,145 @@
+package gpa
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultScale skala nilai huruf ke bobot 4.0 yang umum dipakai perguruan tinggi di Indonesia
+const DefaultScale = "A=4.0,A-=3.7,B+=3.3,B=3.0,B-=2.7,C+=2.3,C=2.0,D=1.0,E=0"
+
+// Scale bobot untuk setiap nilai huruf
+type Scale map[string]float64
+
+// ParseScale membaca format "A=4.0,B+=3.5,...", bobot harus di antara 0 dan 4
+func ParseScale(raw string) (Scale, error) {
+	scale := Scale{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		letter, point, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid grade mapping %q, expected LETTER=POINT", part)
+		}
+		letter = strings.ToUpper(strings.TrimSpace(letter))
+		p, err := strconv.ParseFloat(strings.TrimSpace(point), 64)
+		if err != nil || p < 0 || p > 4 {
+			return nil, fmt.Errorf("invalid point for grade %s, must be between 0 and 4", letter)
+		}
+		scale[letter] = p
+	}
+	if len(scale) == 0 {
+		return nil, fmt.Errorf("grade scale is empty")
+	}
+	return scale, nil
+}
+
+// Point bobot nilai huruf, ok false kalau huruf tidak ada di skala
+func (s Scale) Point(letter string) (float64, bool) {
+	p, ok := s[strings.ToUpper(strings.TrimSpace(letter))]
+	return p, ok
+}
+
+// Letters daftar nilai huruf dari bobot tertinggi
+func (s Scale) Letters() []string {
+	letters := make([]string, 0, len(s))
+	for letter := range s {
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		if s[letters[i]] == s[letters[j]] {
+			return letters[i] < letters[j]
+		}
+		return s[letters[i]] > s[letters[j]]
+	})
+	return letters
+}
+
+// Entry satu mata kuliah yang sudah dinilai
+type Entry struct {
+	CourseID   int64
+	SemesterID int64
+	// SemesterOrder urutan semester (misalnya dari tanggal mulai), dipakai untuk menentukan nilai terakhir
+	SemesterOrder int64
+	Credits       int
+	Letter        string
+}
+
+// Summary hasil perhitungan IP/IPK
+type Summary struct {
+	Credits       int     `json:"credits"`
+	QualityPoints float64 `json:"quality_points"`
+	GPA           float64 `json:"gpa"`
+}
+
+// SemesterSummary IP satu semester dan IPK sampai semester tersebut
+type SemesterSummary struct {
+	SemesterID int64   `json:"semester_id"`
+	Semester   Summary `json:"semester"`
+	Cumulative Summary `json:"cumulative"`
+}
+
+// Compute menghitung IP per semester dan IPK. Mata kuliah yang diulang hanya dihitung
+// sekali di IPK memakai nilai dari semester terakhir, tetapi tetap dihitung di IP semesternya.
+// Nilai huruf yang tidak ada di skala diabaikan
+func (s Scale) Compute(entries []Entry) ([]SemesterSummary, Summary) {
+	var valid []Entry
+	for _, e := range entries {
+		if _, ok := s.Point(e.Letter); ok && e.Credits > 0 {
+			valid = append(valid, e)
+		}
+	}
+	sort.SliceStable(valid, func(i, j int) bool {
+		if valid[i].SemesterOrder == valid[j].SemesterOrder {
+			return valid[i].SemesterID < valid[j].SemesterID
+		}
+		return valid[i].SemesterOrder < valid[j].SemesterOrder
+	})
+
+	var semesters []SemesterSummary
+	var cumulative Summary
+	latest := map[int64]Entry{}
+	for i := 0; i < len(valid); {
+		// satu semester diproses sekaligus
+		j := i
+		var semester Summary
+		for ; j < len(valid) && valid[j].SemesterID == valid[i].SemesterID; j++ {
+			s.add(&semester, valid[j])
+			latest[valid[j].CourseID] = valid[j]
+		}
+		finish(&semester)
+
+		cumulative = Summary{}
+		for _, e := range latest {
+			s.add(&cumulative, e)
+		}
+		finish(&cumulative)
+
+		semesters = append(semesters, SemesterSummary{SemesterID: valid[i].SemesterID, Semester: semester, Cumulative: cumulative})
+		i = j
+	}
+	return semesters, cumulative
+}
+
+func (s Scale) add(summary *Summary, e Entry) {
+	p, _ := s.Point(e.Letter)
+	summary.Credits += e.Credits
+	summary.QualityPoints += p * float64(e.Credits)
+}
+
+func finish(summary *Summary) {
+	summary.QualityPoints = round(summary.QualityPoints)
+	if summary.Credits > 0 {
+		summary.GPA = round(summary.QualityPoints / float64(summary.Credits))
+	}
+}
+
+// dibulatkan 2 angka di belakang koma seperti di transkrip
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
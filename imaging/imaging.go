@@ -0,0 +1,201 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+const (
+	VariantThumbnail = "thumbnail"
+	VariantMedium    = "medium"
+)
+
+// Processor pengaturan pipeline gambar, ukuran variant adalah sisi terpanjang dalam pixel.
+// MaxPixels batas lebar x tinggi yang boleh diproses, lebih ketat dari batas upload karena satu gambar
+// bisa memakai beberapa kali lebar x tinggi x 4 byte selama diproses. Processor dari NewProcessor
+// membatasi jumlah gambar yang diproses bersamaan, Processor kosong tidak dibatasi
+type Processor struct {
+	ThumbnailSize int
+	MediumSize    int
+	JPEGQuality   int
+	MaxPixels     int64
+
+	sem chan struct{}
+}
+
+// ErrTooManyPixels gambar melebihi MaxPixels
+var ErrTooManyPixels = errors.New("imaging: image has too many pixels to process")
+
+func NewProcessor(thumbnailSize, mediumSize, jpegQuality int, maxPixels int64, concurrency int) Processor {
+	p := Processor{
+		ThumbnailSize: thumbnailSize,
+		MediumSize:    mediumSize,
+		JPEGQuality:   jpegQuality,
+		MaxPixels:     maxPixels,
+	}
+	if concurrency > 0 {
+		p.sem = make(chan struct{}, concurrency)
+	}
+	return p
+}
+
+// Image hasil encode, Ext termasuk titik (".jpg")
+type Image struct {
+	Data        []byte
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// Result gambar asli yang sudah dibersihkan beserta variant-nya
+type Result struct {
+	Original Image
+	Variants map[string]Image
+}
+
+// Supported true kalau content type bisa diproses pipeline
+func Supported(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/webp":
+		return true
+	}
+	return false
+}
+
+// Process decode gambar, menerapkan orientasi EXIF, lalu encode ulang tanpa metadata apa pun (EXIF, GPS, ICC).
+// PNG tetap PNG supaya transparansi tidak hilang, JPEG dan WebP menjadi JPEG karena tidak ada encoder WebP.
+// Variant diperkecil dari gambar asli dan tidak pernah diperbesar
+func (p Processor) Process(r io.Reader, contentType string) (*Result, error) {
+	if !Supported(contentType) {
+		return nil, fmt.Errorf("imaging: unsupported content type %s", contentType)
+	}
+	if p.sem != nil {
+		p.sem <- struct{}{}
+		defer func() { <-p.sem }()
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("imaging: decode image: %w", err)
+	}
+	if p.MaxPixels > 0 && int64(config.Width)*int64(config.Height) > p.MaxPixels {
+		return nil, fmt.Errorf("%w: image is %dx%d, the limit is %d pixels",
+			ErrTooManyPixels, config.Width, config.Height, p.MaxPixels)
+	}
+	rotation := 1
+	if contentType == "image/jpeg" {
+		rotation = orientation(data)
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("imaging: decode image: %w", err)
+	}
+	// file mentah tidak dibutuhkan lagi setelah decode
+	data = nil
+
+	// JPEG tanpa rotasi di encode langsung dari YCbCr, tidak perlu salinan NRGBA ukuran penuh
+	var img image.Image = decoded
+	if _, ok := decoded.(*image.YCbCr); !ok || rotation != 1 {
+		img = applyOrientation(toNRGBA(decoded), rotation)
+	}
+	decoded = nil
+
+	asPNG := contentType == "image/png"
+	original, err := p.encode(img, asPNG)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Original: original, Variants: map[string]Image{}}
+
+	// thumbnail diperkecil dari medium supaya gambar besar tidak di scale dua kali dari ukuran penuh
+	medium := fit(img, p.MediumSize)
+	img = nil
+	thumbnail := fit(medium, p.ThumbnailSize)
+	for name, variant := range map[string]image.Image{VariantMedium: medium, VariantThumbnail: thumbnail} {
+		encoded, err := p.encode(variant, asPNG)
+		if err != nil {
+			return nil, err
+		}
+		result.Variants[name] = encoded
+	}
+	return result, nil
+}
+
+func (p Processor) encode(img image.Image, asPNG bool) (Image, error) {
+	var buf bytes.Buffer
+	bounds := img.Bounds()
+	out := Image{Width: bounds.Dx(), Height: bounds.Dy()}
+	if asPNG {
+		if err := png.Encode(&buf, img); err != nil {
+			return out, err
+		}
+		out.Data, out.ContentType, out.Ext = buf.Bytes(), "image/png", ".png"
+		return out, nil
+	}
+
+	// JPEG tidak punya alpha, bagian transparan jadi putih. YCbCr selalu opaque jadi tidak perlu disalin
+	if _, ok := img.(*image.YCbCr); !ok {
+		opaque := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(opaque, opaque.Rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(opaque, opaque.Rect, img, bounds.Min, draw.Over)
+		img = opaque
+	}
+	quality := p.JPEGQuality
+	if quality < 1 || quality > 100 {
+		quality = jpeg.DefaultQuality
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return out, err
+	}
+	out.Data, out.ContentType, out.Ext = buf.Bytes(), "image/jpeg", ".jpg"
+	return out, nil
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok && nrgba.Rect.Min == (image.Point{}) {
+		return nrgba
+	}
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Rect, img, b.Min, draw.Src)
+	return dst
+}
+
+// fit memperkecil gambar supaya sisi terpanjangnya maksimal size, gambar yang sudah kecil dikembalikan apa adanya
+func fit(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if size < 1 || (w <= size && h <= size) {
+		return img
+	}
+	if w >= h {
+		h = max(1, h*size/w)
+		w = size
+	} else {
+		w = max(1, w*size/h)
+		h = size
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Rect, img, bounds, draw.Src, nil)
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
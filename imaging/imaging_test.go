@@ -0,0 +1,287 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// markedJPEG JPEG w x h putih dengan blok merah 8x8 di pojok kiri atas, exif disisipkan setelah SOI
+func markedJPEG(t *testing.T, w, h int, exif []byte) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			if x < 8 && y < 8 {
+				c = color.RGBA{R: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if exif == nil {
+		return data
+	}
+	out := append([]byte{}, data[:2]...)
+	out = append(out, exif...)
+	return append(out, data[2:]...)
+}
+
+// app1 segment APP1 berisi payload
+func app1(payload []byte) []byte {
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// exifTIFF payload Exif dengan IFD0 berisi satu tag Orientation
+func exifTIFF(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+	return append([]byte("Exif\x00\x00"), tiff...)
+}
+
+func isRed(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r>>8 > 200 && g>>8 < 80 && b>>8 < 80
+}
+
+// hasAPP1 true kalau ada segment APP1 sebelum data gambar (SOS)
+func hasAPP1(data []byte) bool {
+	pos := 2
+	for pos+4 <= len(data) && data[pos] == 0xFF {
+		marker := data[pos+1]
+		if marker == 0xDA {
+			return false
+		}
+		if marker == 0xE1 {
+			return true
+		}
+		pos += 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+	}
+	return false
+}
+
+func TestProcessOrientation(t *testing.T) {
+	const w, h = 32, 16
+	tests := []struct {
+		orientation   int
+		width, height int
+		// pojok tempat blok merah berada setelah orientasi diterapkan
+		right, bottom bool
+	}{
+		{1, w, h, false, false},
+		{2, w, h, true, false},
+		{3, w, h, true, true},
+		{4, w, h, false, true},
+		{5, h, w, false, false},
+		{6, h, w, true, false},
+		{7, h, w, true, true},
+		{8, h, w, false, true},
+	}
+	p := Processor{ThumbnailSize: 256, MediumSize: 1024}
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		for _, tt := range tests {
+			data := markedJPEG(t, w, h, app1(exifTIFF(order, uint16(tt.orientation))))
+			if got := orientation(data); got != tt.orientation {
+				t.Fatalf("%v orientation %d: read %d", order, tt.orientation, got)
+			}
+
+			result, err := p.Process(bytes.NewReader(data), "image/jpeg")
+			if err != nil {
+				t.Fatalf("%v orientation %d: %v", order, tt.orientation, err)
+			}
+			img, err := jpeg.Decode(bytes.NewReader(result.Original.Data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := img.Bounds()
+			if b.Dx() != tt.width || b.Dy() != tt.height {
+				t.Fatalf("orientation %d: size %dx%d, want %dx%d", tt.orientation, b.Dx(), b.Dy(), tt.width, tt.height)
+			}
+			x, y := 3, 3
+			if tt.right {
+				x = b.Dx() - 4
+			}
+			if tt.bottom {
+				y = b.Dy() - 4
+			}
+			if !isRed(img.At(x, y)) {
+				t.Errorf("orientation %d: expected red corner at %d,%d, got %v", tt.orientation, x, y, img.At(x, y))
+			}
+		}
+	}
+}
+
+func TestOrientationMalformed(t *testing.T) {
+	valid := exifTIFF(binary.BigEndian, 6)
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not a jpeg", []byte("GIF89a")},
+		{"empty", nil},
+		{"no exif", markedJPEG(t, 8, 8, nil)},
+		{"segment longer than file", []byte{0xFF, 0xD8, 0xFF, 0xE1, 0x40, 0x00, 'E', 'x'}},
+		{"segment length too small", []byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x01}},
+		{"truncated tiff header", append([]byte{0xFF, 0xD8}, app1(valid[:6+4])...)},
+		{"unknown byte order", append([]byte{0xFF, 0xD8}, app1(append([]byte("Exif\x00\x00XX"), valid[8:]...))...)},
+		{"ifd offset out of range", func() []byte {
+			payload := append([]byte{}, valid...)
+			binary.BigEndian.PutUint32(payload[6+4:], 4096)
+			return append([]byte{0xFF, 0xD8}, app1(payload)...)
+		}()},
+		{"entry count beyond data", func() []byte {
+			payload := append([]byte{}, valid...)
+			binary.BigEndian.PutUint16(payload[6+8:], 200)
+			binary.BigEndian.PutUint16(payload[6+10:], 0x0100)
+			return append([]byte{0xFF, 0xD8}, app1(payload)...)
+		}()},
+		{"orientation out of range", append([]byte{0xFF, 0xD8}, app1(exifTIFF(binary.BigEndian, 9))...)},
+		{"orientation zero", append([]byte{0xFF, 0xD8}, app1(exifTIFF(binary.BigEndian, 0))...)},
+	}
+	for _, tt := range tests {
+		if got := orientation(tt.data); got != 1 {
+			t.Errorf("%s: orientation %d, want 1", tt.name, got)
+		}
+	}
+
+	// exif rusak tidak membuat gambar ditolak, gambar diproses tanpa rotasi
+	data := markedJPEG(t, 32, 16, app1(append([]byte("Exif\x00\x00XX"), valid[8:]...)))
+	result, err := Processor{}.Process(bytes.NewReader(data), "image/jpeg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Original.Width != 32 || result.Original.Height != 16 {
+		t.Fatalf("size %dx%d, want 32x16", result.Original.Width, result.Original.Height)
+	}
+}
+
+func TestProcessVariantSizes(t *testing.T) {
+	p := Processor{ThumbnailSize: 64, MediumSize: 200}
+	tests := []struct {
+		width, height int
+		medium        [2]int
+		thumbnail     [2]int
+	}{
+		// lebih kecil dari semua variant, tidak diperbesar
+		{40, 20, [2]int{40, 20}, [2]int{40, 20}},
+		// di antara thumbnail dan medium
+		{120, 60, [2]int{120, 60}, [2]int{64, 32}},
+		{300, 100, [2]int{200, 66}, [2]int{64, 21}},
+		{100, 400, [2]int{50, 200}, [2]int{16, 64}},
+	}
+	for _, tt := range tests {
+		result, err := p.Process(bytes.NewReader(markedJPEG(t, tt.width, tt.height, nil)), "image/jpeg")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, want := range map[string][2]int{VariantMedium: tt.medium, VariantThumbnail: tt.thumbnail} {
+			got := result.Variants[name]
+			if got.Width != want[0] || got.Height != want[1] {
+				t.Errorf("%dx%d %s: %dx%d, want %dx%d", tt.width, tt.height, name, got.Width, got.Height, want[0], want[1])
+			}
+			if got.Width > tt.width || got.Height > tt.height {
+				t.Errorf("%dx%d %s upscaled to %dx%d", tt.width, tt.height, name, got.Width, got.Height)
+			}
+		}
+	}
+}
+
+func TestProcessStripsMetadata(t *testing.T) {
+	data := markedJPEG(t, 32, 16, app1(exifTIFF(binary.LittleEndian, 3)))
+	if !hasAPP1(data) {
+		t.Fatal("test image should carry APP1")
+	}
+	result, err := Processor{ThumbnailSize: 8, MediumSize: 16}.Process(bytes.NewReader(data), "image/jpeg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	images := map[string]Image{"original": result.Original}
+	for name, variant := range result.Variants {
+		images[name] = variant
+	}
+	for name, img := range images {
+		if img.ContentType != "image/jpeg" {
+			t.Errorf("%s: content type %s", name, img.ContentType)
+		}
+		if hasAPP1(img.Data) {
+			t.Errorf("%s: output still contains an APP1 segment", name)
+		}
+	}
+}
+
+func TestProcessPNG(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 20, 10))
+	src.Set(0, 0, color.NRGBA{R: 255, A: 128})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Processor{ThumbnailSize: 5}.Process(&buf, "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Original.ContentType != "image/png" || result.Variants[VariantThumbnail].ContentType != "image/png" {
+		t.Fatalf("png should stay png, got %s", result.Original.ContentType)
+	}
+	img, err := png.Decode(bytes.NewReader(result.Original.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, a := img.At(0, 0).RGBA(); a>>8 != 128 {
+		t.Errorf("alpha %d, want 128", a>>8)
+	}
+}
+
+func TestProcessPixelLimit(t *testing.T) {
+	data := markedJPEG(t, 32, 16, nil)
+	_, err := Processor{MaxPixels: 32*16 - 1}.Process(bytes.NewReader(data), "image/jpeg")
+	if !errors.Is(err, ErrTooManyPixels) {
+		t.Fatalf("error %v, want ErrTooManyPixels", err)
+	}
+	if _, err := (Processor{MaxPixels: 32 * 16}).Process(bytes.NewReader(data), "image/jpeg"); err != nil {
+		t.Fatalf("image at the limit: %v", err)
+	}
+}
+
+func TestProcessorConcurrency(t *testing.T) {
+	p := NewProcessor(8, 16, 80, 0, 1)
+	data := markedJPEG(t, 32, 16, nil)
+	done := make(chan error, 4)
+	for i := 0; i < cap(done); i++ {
+		go func() {
+			_, err := p.Process(bytes.NewReader(data), "image/jpeg")
+			done <- err
+		}()
+	}
+	for i := 0; i < cap(done); i++ {
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(p.sem) != 0 {
+		t.Fatalf("semaphore not released, %d slots in use", len(p.sem))
+	}
+}
@@ -0,0 +1,136 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+// orientation membaca tag Orientation (0x0112) dari EXIF JPEG, 1 kalau tidak ada atau tidak bisa dibaca.
+// Hanya IFD0 yang dibaca, tag lain tidak dibutuhkan karena semua metadata dibuang saat re-encode
+func orientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		// SOS, setelah ini isinya data gambar
+		if marker == 0xDA {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8:]))
+			if value < 1 || value > 8 {
+				return 1
+			}
+			return value
+		}
+	}
+	return 1
+}
+
+// applyOrientation memutar / membalik gambar sesuai nilai Orientation EXIF supaya
+// foto dari kamera HP tetap tegak setelah EXIF dibuang. Orientasi 2 sampai 4 dibalik di tempat,
+// 5 sampai 8 menukar lebar dan tinggi jadi butuh buffer baru
+func applyOrientation(src *image.NRGBA, orientation int) *image.NRGBA {
+	switch {
+	case orientation <= 1 || orientation > 8:
+		return src
+	case orientation == 2: // cermin horizontal
+		mirrorHorizontal(src)
+		return src
+	case orientation == 3: // putar 180
+		mirrorHorizontal(src)
+		mirrorVertical(src)
+		return src
+	case orientation == 4: // cermin vertikal
+		mirrorVertical(src)
+		return src
+	}
+
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, h, w))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 5: // transpose
+				dx, dy = y, x
+			case 6: // putar 90 searah jarum jam
+				dx, dy = h-1-y, x
+			case 7: // transverse
+				dx, dy = h-1-y, w-1-x
+			case 8: // putar 90 berlawanan jarum jam
+				dx, dy = y, w-1-x
+			}
+			s := src.PixOffset(src.Rect.Min.X+x, src.Rect.Min.Y+y)
+			d := dst.PixOffset(dx, dy)
+			copy(dst.Pix[d:d+4], src.Pix[s:s+4])
+		}
+	}
+	return dst
+}
+
+func mirrorHorizontal(img *image.NRGBA) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	for y := 0; y < h; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+w*4]
+		for l, r := 0, (w-1)*4; l < r; l, r = l+4, r-4 {
+			for i := 0; i < 4; i++ {
+				row[l+i], row[r+i] = row[r+i], row[l+i]
+			}
+		}
+	}
+}
+
+func mirrorVertical(img *image.NRGBA) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	for top, bottom := 0, h-1; top < bottom; top, bottom = top+1, bottom-1 {
+		a := img.Pix[top*img.Stride : top*img.Stride+w*4]
+		b := img.Pix[bottom*img.Stride : bottom*img.Stride+w*4]
+		for i := range a {
+			a[i], b[i] = b[i], a[i]
+		}
+	}
+}
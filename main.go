@@ -2,11 +2,16 @@ package main
 
 import (
 	"context"
+	"ginDatabaseMhs/cfg"
 	"ginDatabaseMhs/database"
+	"ginDatabaseMhs/gpa"
+	"ginDatabaseMhs/imaging"
+	"ginDatabaseMhs/model/entity"
 	"ginDatabaseMhs/router"
+	"ginDatabaseMhs/search"
 	"ginDatabaseMhs/service"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"ginDatabaseMhs/storage"
+	"ginDatabaseMhs/upload"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	log "github.com/sirupsen/logrus"
@@ -31,6 +36,11 @@ func main() {
 	// ENV
 	loadEnv()
 
+	conf, err := cfg.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading config %v", err)
+	}
+
 	// pr
 	// INITAL DATABASE
 	db, err := database.Databaseinit(ctx)
@@ -43,16 +53,73 @@ func main() {
 		log.Fatalf("Error running schema migration %v", err)
 	}
 
-	// Initialize AWS S3 client
-	s3Config, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion("us-east-1"))
+	// lampiran yang tersimpan di S3 hanya bisa dibaca dan dihapus kalau bucket dikonfigurasi
+	if conf.S3BucketName == "" {
+		var s3Files int64
+		if err := db.Model(&entity.Attachment{}).Where("storage = ?", storage.DriverS3).Count(&s3Files).Error; err != nil {
+			log.Fatalf("Error checking attachment storage %v", err)
+		}
+		if s3Files > 0 {
+			log.Fatalf("S3_BUCKET_NAME is required, %d attachments are stored in S3", s3Files)
+		}
+	}
+
+	// storage lampiran, file baru disimpan sesuai STORAGE_DRIVER
+	store, err := storage.NewSet(ctx, storage.Config{
+		Driver:      conf.StorageDriver,
+		Path:        conf.StoragePath,
+		S3Bucket:    conf.S3BucketName,
+		S3Region:    conf.S3Region,
+		S3Endpoint:  conf.S3Endpoint,
+		S3PathStyle: conf.S3PathStyle,
+	})
 	if err != nil {
-		log.Fatal(err)
+		log.Fatalf("Error initializing storage %v", err)
 	}
-	s3Client := s3.NewFromConfig(s3Config)
 
 	// initial repo
-	todoRepo := database.NewMahasiswaRepository(db, s3Client)
-	todoService := service.NewMahasiswaService(todoRepo)
+	todoRepo := database.NewMahasiswaRepository(db, store)
+
+	// index pencarian, repository dibungkus supaya index selalu sinkron dengan data
+	var searchIndex search.Index
+	switch conf.SearchDriver {
+	case "memory":
+		memoryIndex := search.NewMemoryIndex()
+		err = todoRepo.StreamAll(func(data entity.User_data) error {
+			return memoryIndex.Index(search.DocumentFrom(data))
+		})
+		if err != nil {
+			log.Fatalf("Error building search index %v", err)
+		}
+		searchIndex = memoryIndex
+	default:
+		searchIndex = search.NewMySQLIndex(db)
+	}
+	indexedRepo := search.NewIndexedRepository(todoRepo, searchIndex)
+	gradeScale := conf.GradeScale
+	if gradeScale == "" {
+		gradeScale = gpa.DefaultScale
+	}
+	scale, err := gpa.ParseScale(gradeScale)
+	if err != nil {
+		log.Fatalf("Error parsing grade scale %v", err)
+	}
+	uploadPolicy, err := upload.NewPolicy(conf.UploadCategories, map[string]int64{
+		upload.CategoryImage:    conf.UploadMaxImageSize,
+		upload.CategoryDocument: conf.UploadMaxDocumentSize,
+	}, conf.UploadMaxImageDimension, conf.UploadMaxImagePixels)
+	if err != nil {
+		log.Fatalf("Error parsing upload policy %v", err)
+	}
+	imageProcessor := imaging.NewProcessor(conf.ImageThumbnailSize, conf.ImageMediumSize, conf.ImageJPEGQuality,
+		conf.ImageMaxPixels, conf.ImageMaxConcurrency)
+	todoService := service.NewMahasiswaService(indexedRepo, searchIndex, store, uploadPolicy, imageProcessor, conf.PresignExpiry, scale, conf.AttendanceThreshold, conf.StatsCacheTTL)
+
+	// hapus permanen data di trash yang sudah melewati masa retention
+	go service.RunTrashPurger(ctx, indexedRepo, conf.TrashRetention, conf.TrashPurgeInterval)
+	// bersihkan upload presigned yang tidak pernah diselesaikan
+	go service.RunUploadSweeper(ctx, indexedRepo, conf.UploadSweepInterval)
+
 	routeBuilder := router.NewRouteBuilder(todoService)
 	routeInit := routeBuilder.RouteInit()
 	err = routeInit.Run(":8080")
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/respErr"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// AdminOnly dipasang setelah Authmiddleware, hanya token dengan role admin yang diteruskan
+func AdminOnly() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetString("role") != entity.RoleAdmin {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, respErr.ErrorResponse{
+				Message: "Only admin can access this endpoint",
+				Status:  http.StatusForbidden,
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -63,15 +63,6 @@ func Authmiddleware() gin.HandlerFunc {
 		ctx.Set("user_id", claims.UserID)
 		ctx.Set("role", claims.Role) // Menambahkan data peran ke konteks
 
-		// Pengecekan peran admin
-		if claims.Role != "admin" {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, respErr.ErrorResponse{
-				Message: "Unauthorized: Only admin can access this endpoint",
-				Status:  http.StatusUnauthorized,
-			})
-			return
-		}
-
 		// Melanjutkan ke handler jika semua pengecekan berhasil
 		ctx.Next()
 	}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID memberi setiap request sebuah id, dipakai untuk log dan history perubahan data
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = uuid.NewString()
+		}
+
+		ctx.Set("request_id", requestID)
+		ctx.Header(RequestIDHeader, requestID)
+		ctx.Next()
+	}
+}
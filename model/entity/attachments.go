@@ -1,11 +1,65 @@
 package entity
 
-import "time"
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"path"
+	"strings"
+	"time"
+)
 
+// Attachment Path berisi key file di storage, bukan URL. Storage driver tempat file disimpan
+// (local, s3 atau memory), kosong berarti driver default. Variants nama variant gambar (thumbnail, medium) yang disimpan di sebelah file asli
 type Attachment struct {
-	ID              int64     `gorm:"primaryKey" json:"id"`
-	UserID          int64     `gorm:"index" json:"user_id"`
-	Path            string    `gorm:"type:varchar(255)" json:"path"`
-	AttachmentOrder int64     `json:"attachment_order"`
-	Timestamp       time.Time `gorm:"default:current_timestamp" json:"timestamp"`
+	ID              int64      `gorm:"primaryKey" json:"id"`
+	UserID          int64      `gorm:"index" json:"user_id"`
+	Path            string     `gorm:"type:varchar(255)" json:"path"`
+	Storage         string     `gorm:"type:varchar(16)" json:"-"`
+	FileName        string     `gorm:"type:varchar(255)" json:"file_name"`
+	ContentType     string     `gorm:"type:varchar(127)" json:"content_type"`
+	Size            int64      `json:"size"`
+	Variants        StringList `gorm:"type:json" json:"-"`
+	AttachmentOrder int64      `json:"attachment_order"`
+	Timestamp       time.Time  `gorm:"default:current_timestamp" json:"timestamp"`
+
+	URL         string            `gorm:"-" json:"url"`
+	VariantURLs map[string]string `gorm:"-" json:"variant_urls,omitempty"`
+}
+
+// VariantKey key variant di storage, "abc.jpg" menjadi "abc_thumbnail.jpg"
+func (a Attachment) VariantKey(variant string) string {
+	ext := path.Ext(a.Path)
+	return strings.TrimSuffix(a.Path, ext) + "_" + variant + ext
+}
+
+// HasVariant true kalau variant tersebut dibuat saat upload
+func (a Attachment) HasVariant(variant string) bool {
+	for _, v := range a.Variants {
+		if v == variant {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Attachment) AfterFind(tx *gorm.DB) error {
+	a.fillURLs()
+	return nil
+}
+
+func (a *Attachment) AfterCreate(tx *gorm.DB) error {
+	a.fillURLs()
+	return nil
+}
+
+// fillURLs URL download lewat endpoint /attachments/:id, bukan URL storage langsung
+func (a *Attachment) fillURLs() {
+	a.URL = fmt.Sprintf("/attachments/%d", a.ID)
+	a.VariantURLs = nil
+	for _, variant := range a.Variants {
+		if a.VariantURLs == nil {
+			a.VariantURLs = map[string]string{}
+		}
+		a.VariantURLs[variant] = a.URL + "?variant=" + variant
+	}
 }
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+const (
+	AttendancePresent   = "present"
+	AttendanceAbsent    = "absent"
+	AttendanceSick      = "sick"
+	AttendancePermitted = "permitted"
+)
+
+var AttendanceStatuses = []string{AttendancePresent, AttendanceAbsent, AttendanceSick, AttendancePermitted}
+
+// Attendance kehadiran satu mahasiswa (user_data) di satu pertemuan
+type Attendance struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	SessionID int64     `gorm:"uniqueIndex:idx_attendance_record" json:"session_id"`
+	RecordID  int64     `gorm:"uniqueIndex:idx_attendance_record" json:"record_id"`
+	Status    string    `gorm:"type:varchar(16)" json:"status"`
+	Note      string    `gorm:"type:varchar(255)" json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AttendanceStat rekap kehadiran satu mahasiswa di satu mata kuliah dan semester
+type AttendanceStat struct {
+	RecordID   int64  `json:"record_id"`
+	Name       string `json:"name"`
+	CourseID   int64  `json:"course_id"`
+	SemesterID int64  `json:"semester_id"`
+	Sessions   int64  `json:"sessions"`
+	Present    int64  `json:"present"`
+	Absent     int64  `json:"absent"`
+	Sick       int64  `json:"sick"`
+	Permitted  int64  `json:"permitted"`
+}
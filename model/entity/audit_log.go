@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+const (
+	AuditEntityUserData   = "user_data"
+	AuditEntityAttachment = "attachments"
+
+	AuditActionCreate   = "create"
+	AuditActionUpdate   = "update"
+	AuditActionDelete   = "delete"
+	AuditActionRestore  = "restore"
+	AuditActionPurge    = "purge"
+	AuditActionRevert   = "revert"
+	AuditActionMerge    = "merge"
+	AuditActionReassign = "reassign"
+)
+
+// FieldChange nilai sebelum dan sesudah dari satu field
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AuditLog struct {
+	ID         int64     `gorm:"primaryKey" json:"id"`
+	EntityType string    `gorm:"type:varchar(32)" json:"entity_type"`
+	EntityID   int64     `json:"entity_id"`
+	RecordID   int64     `gorm:"index" json:"record_id"`
+	Version    int       `json:"version"`
+	Action     string    `gorm:"type:varchar(16)" json:"action"`
+	ActorID    int64     `json:"actor_id"`
+	RequestID  string    `gorm:"type:varchar(64)" json:"request_id"`
+	Changes    JSON      `gorm:"type:json" json:"changes"`
+	Snapshot   JSON      `gorm:"type:json" json:"snapshot"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// ClassSession satu pertemuan kuliah dari mata kuliah di satu semester
+type ClassSession struct {
+	ID            int64     `gorm:"primaryKey" json:"id"`
+	UserID        int64     `gorm:"index" json:"user_id"`
+	CourseID      int64     `gorm:"uniqueIndex:idx_class_session_meeting" json:"course_id"`
+	SemesterID    int64     `gorm:"uniqueIndex:idx_class_session_meeting" json:"semester_id"`
+	MeetingNumber int       `gorm:"uniqueIndex:idx_class_session_meeting" json:"meeting_number"`
+	Date          Date      `gorm:"type:date" json:"date"`
+	Topic         string    `gorm:"type:varchar(255)" json:"topic"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+const (
+	CommentVisibilityPrivate = "private"
+	CommentVisibilityShared  = "shared"
+)
+
+// Comment catatan pada data mahasiswa. Private hanya terlihat oleh penulisnya, shared terlihat oleh
+// semua user yang punya akses ke data. Comment ikut tersimpan selama data ada di trash dan
+// baru terhapus saat data dihapus permanen
+type Comment struct {
+	ID         int64     `gorm:"primaryKey" json:"id"`
+	RecordID   int64     `gorm:"index" json:"record_id"`
+	AuthorID   int64     `json:"author_id"`
+	Author     string    `gorm:"->;-:migration" json:"author"`
+	Body       string    `gorm:"type:text" json:"body"`
+	Visibility string    `gorm:"type:varchar(16)" json:"visibility"`
+	EditCount  int       `json:"edit_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CommentRevision isi comment sebelum diubah
+type CommentRevision struct {
+	ID         int64     `gorm:"primaryKey" json:"id"`
+	CommentID  int64     `gorm:"index" json:"comment_id"`
+	Body       string    `gorm:"type:text" json:"body"`
+	Visibility string    `gorm:"type:varchar(16)" json:"visibility"`
+	EditedBy   int64     `json:"edited_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
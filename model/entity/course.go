@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// Course mata kuliah, Credits adalah jumlah SKS
+type Course struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	UserID    int64     `gorm:"uniqueIndex:idx_course_code" json:"user_id"`
+	Code      string    `gorm:"type:varchar(20);uniqueIndex:idx_course_code" json:"code"`
+	Name      string    `gorm:"type:varchar(255)" json:"name"`
+	Credits   int       `json:"credits"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// CustomField definisi field tambahan untuk data mahasiswa milik UserID,
+// nilainya disimpan di kolom custom_fields pada user_data dengan Key sebagai nama
+type CustomField struct {
+	ID        int64      `gorm:"primaryKey" json:"id"`
+	UserID    int64      `gorm:"uniqueIndex:idx_custom_field_key" json:"user_id"`
+	Key       string     `gorm:"type:varchar(50);uniqueIndex:idx_custom_field_key" json:"key"`
+	Label     string     `gorm:"type:varchar(100)" json:"label"`
+	Type      string     `gorm:"type:varchar(16)" json:"type"`
+	Required  bool       `json:"required"`
+	Options   StringList `gorm:"type:json" json:"options"`
+	Pattern   string     `gorm:"type:varchar(255)" json:"pattern"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
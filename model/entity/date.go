@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const DateLayout = "2006-01-02"
+
+// format ISO-8601 yang diterima, bentuk lengkap dengan jam juga diterima tetapi hanya tanggalnya yang dipakai
+var dateLayouts = []string{DateLayout, "20060102", time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05"}
+
+// Date tanggal tanpa jam untuk kolom DATE, nilai kosong disimpan sebagai NULL
+type Date struct {
+	time.Time
+}
+
+// ParseDate membaca tanggal format ISO-8601, string kosong menghasilkan Date kosong
+func ParseDate(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Date{}, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return NewDate(t), nil
+		}
+	}
+	return Date{}, fmt.Errorf("%q is not a valid ISO-8601 date (YYYY-MM-DD)", s)
+}
+
+func NewDate(t time.Time) Date {
+	return Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateLayout)
+}
+
+// AgeAt umur dalam tahun penuh pada tanggal now
+func (d Date) AgeAt(now time.Time) int {
+	if d.IsZero() {
+		return 0
+	}
+	age := now.Year() - d.Year()
+	if now.Month() < d.Month() || (now.Month() == d.Month() && now.Day() < d.Day()) {
+		age--
+	}
+	return age
+}
+
+func (d Date) GormDataType() string {
+	return "date"
+}
+
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+func (d *Date) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Date{}
+	case time.Time:
+		*d = NewDate(v)
+	case []byte:
+		return d.scanString(string(v))
+	case string:
+		return d.scanString(v)
+	default:
+		return errors.New("unsupported type for Date column")
+	}
+	return nil
+}
+
+func (d *Date) scanString(s string) error {
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return d.scanString(s)
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Enrollment mahasiswa (user_data) yang mengambil satu mata kuliah di satu semester
+type Enrollment struct {
+	ID         int64     `gorm:"primaryKey" json:"id"`
+	RecordID   int64     `gorm:"uniqueIndex:idx_enrollment" json:"record_id"`
+	CourseID   int64     `gorm:"uniqueIndex:idx_enrollment" json:"course_id"`
+	SemesterID int64     `gorm:"uniqueIndex:idx_enrollment" json:"semester_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	Course     *Course   `json:"course,omitempty"`
+	Semester   *Semester `json:"semester,omitempty"`
+	Grade      *Grade    `json:"grade,omitempty"`
+}
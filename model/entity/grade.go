@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// Grade nilai akhir satu enrollment, Letter dikonversi ke bobot lewat skala nilai yang dikonfigurasi
+type Grade struct {
+	ID           int64     `gorm:"primaryKey" json:"id"`
+	EnrollmentID int64     `gorm:"uniqueIndex" json:"enrollment_id"`
+	Letter       string    `gorm:"type:varchar(4)" json:"letter"`
+	Score        *float64  `json:"score,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// PendingUpload upload langsung ke storage lewat presigned URL yang belum dikonfirmasi client.
+// Baris ini dihapus setelah file diverifikasi dan dicatat sebagai Attachment
+type PendingUpload struct {
+	ID          int64     `gorm:"primaryKey" json:"id"`
+	Key         string    `gorm:"type:varchar(255)" json:"key"`
+	RecordID    int64     `json:"record_id"`
+	UserID      int64     `json:"user_id"`
+	FileName    string    `gorm:"type:varchar(255)" json:"file_name"`
+	ContentType string    `gorm:"type:varchar(127)" json:"content_type"`
+	Size        int64     `json:"size"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
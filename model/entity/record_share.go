@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+const (
+	SharePermissionViewer = "viewer"
+	SharePermissionEditor = "editor"
+)
+
+// RecordShare akses ke satu data mahasiswa yang diberikan pemilik ke user lain
+type RecordShare struct {
+	ID         int64      `gorm:"primaryKey" json:"id"`
+	RecordID   int64      `gorm:"uniqueIndex:idx_record_share_user" json:"record_id"`
+	UserID     int64      `gorm:"uniqueIndex:idx_record_share_user" json:"user_id"`
+	Permission string     `gorm:"type:varchar(16)" json:"permission"`
+	GrantedBy  int64      `json:"granted_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Record     *User_data `gorm:"foreignKey:RecordID" json:"record,omitempty"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// Semester periode perkuliahan, contoh "2023/2024 Ganjil"
+type Semester struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	UserID    int64     `gorm:"index" json:"user_id"`
+	Name      string    `gorm:"type:varchar(100)" json:"name"`
+	StartDate Date      `gorm:"type:date" json:"start_date"`
+	EndDate   Date      `gorm:"type:date" json:"end_date"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
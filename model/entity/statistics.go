@@ -0,0 +1,7 @@
+package entity
+
+// GroupCount jumlah data untuk satu nilai hasil GROUP BY, Key kosong berarti nilainya NULL
+type GroupCount struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// Tag label untuk data mahasiswa, UserID nil berarti tag global yang dikelola admin.
+// OwnerKey kolom generated COALESCE(user_id, 0) supaya nama tag global juga unik (NULL tidak bentrok di unique index)
+type Tag struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	UserID    *int64    `gorm:"index:idx_tag_user" json:"user_id"`
+	OwnerKey  int64     `gorm:"->;uniqueIndex:idx_tag_owner_name" json:"-"`
+	Name      string    `gorm:"type:varchar(50);uniqueIndex:idx_tag_owner_name" json:"name"`
+	Color     string    `gorm:"type:varchar(7)" json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Global tag tanpa pemilik, bisa dipakai semua user
+func (t Tag) Global() bool {
+	return t.UserID == nil
+}
+
+// RecordTag relasi many-to-many antara user_data dan tags
+type RecordTag struct {
+	RecordID  int64     `gorm:"primaryKey" json:"record_id"`
+	TagID     int64     `gorm:"primaryKey" json:"tag_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,5 +1,11 @@
 package entity
 
+// role user, admin hanya bisa diberikan oleh admin lain lewat /admin/users/:user_id/role
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID       int64  `gorm:"primaryKey" json:"id"`
 	Username string `gorm:"not null;unique" json:"username"`
@@ -1,13 +1,42 @@
 package entity
 
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// AgeSQL umur yang dihitung dari birthdate, kolom age hanya dipakai kalau birthdate kosong
+const AgeSQL = "COALESCE(TIMESTAMPDIFF(YEAR, birthdate, CURDATE()), age)"
+
 type User_data struct {
-	ID          int64        `gorm:"primaryKey" json:"id"`
-	Email       string       `gorm:"type:varchar(255);uniqueIndex:idx_email_name" json:"email"`
-	Name        string       `gorm:"type:varchar(255);uniqueIndex:idx_email_name" json:"name"`
-	Age         int          `json:"age"`
-	Address     string       `gorm:"type:varchar(100)" json:"address"`
-	Birthdate   string       `gorm:"type:varchar(255)" json:"birthdate"`
-	PhoneNumber string       `gorm:"type:varchar(20)" json:"phone_number"`
-	UserID      int64        `json:"user_id"`
-	Attachments []Attachment `gorm:"foreignKey:user_id" json:"attachments"`
+	ID          int64  `gorm:"primaryKey" json:"id"`
+	Email       string `gorm:"type:varchar(255);uniqueIndex:idx_email_name" json:"email"`
+	Name        string `gorm:"type:varchar(255);uniqueIndex:idx_email_name" json:"name"`
+	Age         int    `json:"age"`
+	Address     string `gorm:"type:varchar(100)" json:"address"`
+	Birthdate   Date   `gorm:"type:date" json:"birthdate"`
+	PhoneNumber string `gorm:"type:varchar(20)" json:"phone_number"`
+	UserID      int64  `json:"user_id"`
+	// CustomFields nilai custom field sesuai definisi CustomField milik UserID
+	CustomFields JSON           `gorm:"type:json" json:"custom_fields,omitempty"`
+	Attachments  []Attachment   `gorm:"foreignKey:user_id" json:"attachments"`
+	Tags         []Tag          `gorm:"many2many:record_tags;joinForeignKey:RecordID;joinReferences:TagID" json:"tags,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// BeforeSave age selalu mengikuti birthdate kalau birthdate diisi
+func (d *User_data) BeforeSave(tx *gorm.DB) error {
+	if !d.Birthdate.IsZero() {
+		d.Age = d.Birthdate.AgeAt(time.Now())
+	}
+	return nil
+}
+
+// AfterFind menghitung ulang age supaya tidak basi setelah ulang tahun
+func (d *User_data) AfterFind(tx *gorm.DB) error {
+	if !d.Birthdate.IsZero() {
+		d.Age = d.Birthdate.AgeAt(time.Now())
+	}
+	return nil
 }
@@ -1,18 +1,26 @@
 package request
 
-import "ginDatabaseMhs/model/entity"
+import "ginDatabaseMhs/pagination"
 
 type MahasiswaResponse struct {
-	Status  interface{}      `json:"status"`
-	Message interface{}      `json:"message"`
-	Data    entity.User_data `json:"data"`
+	Status  interface{} `json:"status"`
+	Message interface{} `json:"message"`
+	Data    interface{} `json:"data"`
 }
 
 type ResponseToGetAll struct {
-	Message string             `json:"message"`
-	UserId  int64              `json:"user_id"`
-	Data    int                `json:"data"`
-	MHS     []entity.User_data `json:"todos"`
+	Message string           `json:"message"`
+	UserId  int64            `json:"user_id"`
+	Data    int              `json:"data"`
+	MHS     interface{}      `json:"todos"`
+	Paging  *pagination.Page `json:"paging,omitempty"`
+}
+
+type ListResponse struct {
+	Status  int              `json:"status"`
+	Message string           `json:"message"`
+	Data    interface{}      `json:"data"`
+	Paging  *pagination.Page `json:"paging,omitempty"`
 }
 
 type IDResponse struct {
@@ -8,6 +8,8 @@ type MahasiswaCreateRequest struct {
 	PhoneNumber string `json:"phoneNumber"`
 	Email       string `json:"email" binding:"required"`
 	UserID      int64  `json:"user_id"`
+	// CustomFields nilai custom field, key sesuai definisi custom field milik user
+	CustomFields map[string]interface{} `json:"custom_fields"`
 }
 
 type MahasiswaUpdateRequest struct {
@@ -17,6 +19,8 @@ type MahasiswaUpdateRequest struct {
 	Birthdate   string `json:"birthdate"`
 	PhoneNumber string `json:"phoneNumber"`
 	Email       string `json:"email" binding:"required"`
+	// CustomFields hanya key yang dikirim yang diubah, nilai null menghapus key
+	CustomFields map[string]interface{} `json:"custom_fields"`
 }
 
 func (r *MahasiswaUpdateRequest) ReqMhs() map[string]interface{} {
@@ -0,0 +1,40 @@
+package request
+
+import "ginDatabaseMhs/gpa"
+
+type CourseRequest struct {
+	Code    string `json:"code" binding:"required,max=20"`
+	Name    string `json:"name" binding:"required,max=255"`
+	Credits int    `json:"credits" binding:"required,min=1,max=24"`
+}
+
+// SemesterRequest tanggal memakai format ISO-8601 (YYYY-MM-DD)
+type SemesterRequest struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+}
+
+type EnrollmentRequest struct {
+	CourseID   int64 `json:"course_id" binding:"required"`
+	SemesterID int64 `json:"semester_id" binding:"required"`
+}
+
+// GradeRequest Score opsional (0-100), Letter harus ada di skala nilai
+type GradeRequest struct {
+	Letter string   `json:"letter" binding:"required"`
+	Score  *float64 `json:"score" binding:"omitempty,min=0,max=100"`
+}
+
+type GPASemester struct {
+	gpa.SemesterSummary
+	Name string `json:"name"`
+}
+
+type GPAResponse struct {
+	Status     int           `json:"status"`
+	RecordID   int64         `json:"record_id"`
+	Scale      gpa.Scale     `json:"scale"`
+	Semesters  []GPASemester `json:"semesters"`
+	Cumulative gpa.Summary   `json:"cumulative"`
+}
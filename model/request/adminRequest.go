@@ -0,0 +1,24 @@
+package request
+
+// Owner data pemilik yang ditampilkan ke admin, tanpa password
+type Owner struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+}
+
+// AdminRecord data mahasiswa beserta pemiliknya, Owner nil kalau user pemilik sudah tidak ada
+type AdminRecord struct {
+	Record interface{} `json:"record"`
+	Owner  *Owner      `json:"owner"`
+}
+
+// ReassignRequest Owner berisi username atau email pemilik baru
+type ReassignRequest struct {
+	Owner string `json:"owner" binding:"required"`
+}
+
+type RoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
@@ -0,0 +1,28 @@
+package request
+
+import "time"
+
+// PresignUploadRequest Size harus sama persis dengan ukuran file yang nanti di upload
+type PresignUploadRequest struct {
+	RecordID int64  `json:"record_id" binding:"required,min=1"`
+	FileName string `json:"file_name" binding:"required,max=255"`
+	Size     int64  `json:"size" binding:"required,min=1"`
+}
+
+type PresignCompleteRequest struct {
+	Key string `json:"key" binding:"required,max=255"`
+}
+
+// PresignedURL Headers wajib dikirim client apa adanya saat memanggil URL
+type PresignedURL struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Key       string            `json:"key,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// AttachmentOrderRequest urutan baru semua lampiran data, dari yang pertama
+type AttachmentOrderRequest struct {
+	AttachmentIDs []int64 `json:"attachment_ids" binding:"required,min=1"`
+}
@@ -0,0 +1,36 @@
+package request
+
+import "ginDatabaseMhs/model/entity"
+
+// ClassSessionRequest tanggal memakai format ISO-8601 (YYYY-MM-DD)
+type ClassSessionRequest struct {
+	CourseID      int64  `json:"course_id" binding:"required"`
+	SemesterID    int64  `json:"semester_id" binding:"required"`
+	MeetingNumber int    `json:"meeting_number" binding:"required,min=1"`
+	Date          string `json:"date" binding:"required"`
+	Topic         string `json:"topic" binding:"max=255"`
+}
+
+type AttendanceEntry struct {
+	RecordID int64  `json:"record_id" binding:"required"`
+	Status   string `json:"status" binding:"required,oneof=present absent sick permitted"`
+	Note     string `json:"note" binding:"max=255"`
+}
+
+type AttendanceBulkRequest struct {
+	Records []AttendanceEntry `json:"records" binding:"required,min=1,dive"`
+}
+
+// AttendanceReport persentase kehadiran dihitung dari status present dibagi jumlah pertemuan
+type AttendanceReport struct {
+	entity.AttendanceStat
+	Percentage     float64 `json:"percentage"`
+	BelowThreshold bool    `json:"below_threshold"`
+}
+
+type AttendanceReportResponse struct {
+	Status    int                `json:"status"`
+	Threshold float64            `json:"threshold"`
+	Flagged   int                `json:"flagged"`
+	Data      []AttendanceReport `json:"data"`
+}
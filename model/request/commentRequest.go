@@ -0,0 +1,7 @@
+package request
+
+// CommentRequest Visibility kosong berarti shared saat membuat comment dan tidak berubah saat mengubah comment
+type CommentRequest struct {
+	Body       string `json:"body" binding:"required,max=10000"`
+	Visibility string `json:"visibility" binding:"omitempty,oneof=private shared"`
+}
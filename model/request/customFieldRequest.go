@@ -0,0 +1,21 @@
+package request
+
+// CustomFieldRequest Options wajib untuk type select, Pattern (regex) hanya untuk type text
+// OwnerID pemilik definisi, kosong berarti milik admin yang membuat
+type CustomFieldRequest struct {
+	OwnerID  int64    `json:"owner_id"`
+	Key      string   `json:"key" binding:"required,max=50"`
+	Label    string   `json:"label" binding:"required,max=100"`
+	Type     string   `json:"type" binding:"required,oneof=text number date boolean select"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options" binding:"max=100"`
+	Pattern  string   `json:"pattern" binding:"max=255"`
+}
+
+// CustomFieldUpdateRequest key dan type tidak bisa diubah supaya nilai yang sudah tersimpan tetap valid
+type CustomFieldUpdateRequest struct {
+	Label    string   `json:"label" binding:"required,max=100"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options" binding:"max=100"`
+	Pattern  string   `json:"pattern" binding:"max=255"`
+}
@@ -0,0 +1,31 @@
+package request
+
+import "gorm.io/gorm/clause"
+
+// MahasiswaFilter filter yang dipakai bersama oleh search dan export
+type MahasiswaFilter struct {
+	// Search dicocokkan ke name, email, address dan phone_number
+	Search    string
+	AgeMin    *int
+	AgeMax    *int
+	BirthFrom string
+	BirthTo   string
+	// Tags nama tag, data cukup punya salah satu kecuali TagsAll true
+	Tags    []string
+	TagsAll bool
+	// Where hasil kompilasi parameter filter (RSQL)
+	Where clause.Expression
+}
+
+// SortField satu kolom urutan, Desc true untuk urutan menurun
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// Projection kolom yang diambil dan relasi yang ikut dimuat, Fields kosong berarti semua kolom
+type Projection struct {
+	Fields      []string
+	Attachments bool
+	Tags        bool
+}
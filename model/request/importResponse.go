@@ -0,0 +1,25 @@
+package request
+
+const (
+	ImportModeAllOrNothing = "all_or_nothing"
+	ImportModeBestEffort   = "best_effort"
+)
+
+type ImportRowResult struct {
+	Row    int      `json:"row"`
+	Status string   `json:"status"`
+	ID     int64    `json:"id,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+type ImportResponse struct {
+	Status  int               `json:"status"`
+	Message string            `json:"message"`
+	DryRun  bool              `json:"dry_run"`
+	Mode    string            `json:"mode"`
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Invalid int               `json:"invalid"`
+	Created int               `json:"created"`
+	Rows    []ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,24 @@
+package request
+
+const (
+	MergeKeepSurvivor  = "survivor"
+	MergeKeepDuplicate = "duplicate"
+)
+
+// MergeRequest Fields berisi pilihan nilai per field ("survivor" atau "duplicate"),
+// field yang tidak dipilih memakai nilai survivor kecuali kosong
+type MergeRequest struct {
+	SurvivorID  int64             `json:"survivor_id" binding:"required"`
+	DuplicateID int64             `json:"duplicate_id" binding:"required"`
+	Fields      map[string]string `json:"fields"`
+}
+
+type DuplicatesResponse struct {
+	Status     int         `json:"status"`
+	Threshold  float64     `json:"threshold"`
+	Total      int         `json:"total"`
+	Page       int         `json:"page"`
+	PerPage    int         `json:"per_page"`
+	TotalPages int         `json:"total_pages"`
+	Data       interface{} `json:"data"`
+}
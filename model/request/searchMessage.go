@@ -1,9 +1,26 @@
 package request
 
-import "ginDatabaseMhs/model/entity"
+import "ginDatabaseMhs/pagination"
 
 type SearchResponse struct {
-	Status int                `json:"status"`
-	Data   []entity.User_data `json:"data"`
-	Total  int64              `json:"total"`
+	Status     int              `json:"status"`
+	Data       interface{}      `json:"data"`
+	Total      int64            `json:"total"`
+	Page       int              `json:"page,omitempty"`
+	PerPage    int              `json:"per_page,omitempty"`
+	TotalPages int              `json:"total_pages,omitempty"`
+	Paging     *pagination.Page `json:"paging,omitempty"`
+}
+
+type FuzzySearchHit struct {
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+	Data       interface{}       `json:"data"`
+}
+
+type FuzzySearchResponse struct {
+	Status int              `json:"status"`
+	Query  string           `json:"query"`
+	Total  int              `json:"total"`
+	Data   []FuzzySearchHit `json:"data"`
 }
@@ -0,0 +1,7 @@
+package request
+
+// ShareRequest User berisi username atau email user yang diberi akses
+type ShareRequest struct {
+	User       string `json:"user" binding:"required"`
+	Permission string `json:"permission" binding:"required,oneof=viewer editor"`
+}
@@ -0,0 +1,24 @@
+package request
+
+import (
+	"ginDatabaseMhs/model/entity"
+	"time"
+)
+
+// AgeBucket rentang umur Min sampai Max (inklusif)
+type AgeBucket struct {
+	Label string `json:"label"`
+	Min   int    `json:"min"`
+	Max   int    `json:"max"`
+	Count int64  `json:"count"`
+}
+
+type StatsResponse struct {
+	Status          int                 `json:"status"`
+	Total           int64               `json:"total"`
+	AgeHistogram    []AgeBucket         `json:"age_histogram"`
+	BirthYears      []entity.GroupCount `json:"birth_years"`
+	Cities          []entity.GroupCount `json:"cities"`
+	CreatedPerMonth []entity.GroupCount `json:"created_per_month"`
+	GeneratedAt     time.Time           `json:"generated_at"`
+}
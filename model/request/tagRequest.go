@@ -0,0 +1,20 @@
+package request
+
+// TagRequest Global hanya boleh diisi admin, Color format hex (#rrggbb)
+type TagRequest struct {
+	Name   string `json:"name" binding:"required,max=50"`
+	Color  string `json:"color" binding:"omitempty,hexcolor,max=7"`
+	Global bool   `json:"global"`
+}
+
+// BulkTagRequest semua tag dipasang / dilepas dari semua data
+type BulkTagRequest struct {
+	RecordIDs []int64 `json:"record_ids" binding:"required,min=1,max=500"`
+	TagIDs    []int64 `json:"tag_ids" binding:"required,min=1,max=50"`
+}
+
+type BulkTagResult struct {
+	Records  int   `json:"records"`
+	Tags     int   `json:"tags"`
+	Affected int64 `json:"affected"`
+}
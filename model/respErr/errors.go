@@ -8,3 +8,18 @@ type ErrorResponse struct {
 type Error struct {
 	Error string `json:"error"`
 }
+
+// FilterError error untuk ekspresi filter yang tidak valid, Position menunjuk karakter yang salah
+type FilterError struct {
+	Message  string `json:"message"`
+	Status   int    `json:"status"`
+	Filter   string `json:"filter"`
+	Position int    `json:"position"`
+}
+
+// UploadError penolakan file upload, Code bisa dipakai client untuk menampilkan pesan sendiri
+type UploadError struct {
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+}
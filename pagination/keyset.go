@@ -0,0 +1,191 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Key satu kolom urutan, Expr sudah berasal dari whitelist jadi aman dipakai di query
+type Key struct {
+	Expr string
+	Desc bool
+}
+
+// Cursor posisi terakhir yang sudah dibaca, dikirim ke client dalam bentuk opaque string
+type Cursor struct {
+	Sort     string        `json:"s"`
+	Values   []interface{} `json:"v"`
+	Backward bool          `json:"b,omitempty"`
+}
+
+// Params parameter keyset pagination, id selalu ditambahkan sebagai urutan terakhir supaya urutan stabil
+type Params struct {
+	Keys   []Key
+	Limit  int
+	Cursor *Cursor
+}
+
+// Page info halaman untuk response
+type Page struct {
+	Limit int    `json:"limit"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+}
+
+// NewParams membuat params dari query string, cursor harus dibuat dengan urutan yang sama
+func NewParams(keys []Key, limit int, cursor string) (Params, error) {
+	if limit < 1 || limit > MaxLimit {
+		limit = DefaultLimit
+	}
+	p := Params{Keys: keys, Limit: limit}
+	if cursor == "" {
+		return p, nil
+	}
+
+	c, err := decodeCursor(cursor)
+	if err != nil {
+		return p, err
+	}
+	if c.Sort != p.sortSignature() || len(c.Values) != len(p.keys()) {
+		return p, errors.New("cursor does not match the requested sort")
+	}
+	p.Cursor = c
+	return p, nil
+}
+
+func (p Params) keys() []Key {
+	keys := make([]Key, 0, len(p.Keys)+1)
+	keys = append(keys, p.Keys...)
+	return append(keys, Key{Expr: "id", Desc: p.idDesc()})
+}
+
+// id mengikuti arah urutan kolom pertama, tanpa kolom sort urutannya id dari yang terkecil
+func (p Params) idDesc() bool {
+	return len(p.Keys) > 0 && p.Keys[0].Desc
+}
+
+func (p Params) sortSignature() string {
+	parts := make([]string, 0, len(p.Keys))
+	for _, k := range p.Keys {
+		if k.Desc {
+			parts = append(parts, "-"+k.Expr)
+		} else {
+			parts = append(parts, k.Expr)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func (p Params) backward() bool {
+	return p.Cursor != nil && p.Cursor.Backward
+}
+
+// Scope menambahkan kondisi keyset, urutan dan limit (+1 untuk mengecek halaman berikutnya)
+func (p Params) Scope(db *gorm.DB) *gorm.DB {
+	keys := p.keys()
+	backward := p.backward()
+
+	if p.Cursor != nil {
+		// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+		ors := make([]string, 0, len(keys))
+		var vars []interface{}
+		for i, key := range keys {
+			ands := make([]string, 0, i+1)
+			for j := 0; j < i; j++ {
+				ands = append(ands, keys[j].Expr+" = ?")
+				vars = append(vars, p.Cursor.Values[j])
+			}
+			op := " > ?"
+			if key.Desc != backward {
+				op = " < ?"
+			}
+			ands = append(ands, key.Expr+op)
+			vars = append(vars, p.Cursor.Values[i])
+			ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+		}
+		db = db.Where("("+strings.Join(ors, " OR ")+")", vars...)
+	}
+
+	for _, key := range keys {
+		if key.Desc != backward {
+			db = db.Order(key.Expr + " DESC")
+		} else {
+			db = db.Order(key.Expr + " ASC")
+		}
+	}
+	return db.Limit(p.Limit + 1)
+}
+
+// Paginate memotong baris ekstra, membalik urutan kalau membaca mundur dan membuat cursor next/prev.
+// valuesOf harus mengembalikan nilai setiap Key dengan urutan yang sama, ditambah id di akhir
+func Paginate[T any](p Params, rows []T, valuesOf func(T) []interface{}) ([]T, Page) {
+	page := Page{Limit: p.Limit}
+
+	hasMore := len(rows) > p.Limit
+	if hasMore {
+		rows = rows[:p.Limit]
+	}
+
+	backward := p.backward()
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	if len(rows) == 0 {
+		return rows, page
+	}
+
+	hasNext, hasPrev := hasMore, p.Cursor != nil
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	sort := p.sortSignature()
+	if hasNext {
+		page.Next = encodeCursor(Cursor{Sort: sort, Values: valuesOf(rows[len(rows)-1])})
+	}
+	if hasPrev {
+		page.Prev = encodeCursor(Cursor{Sort: sort, Values: valuesOf(rows[0]), Backward: true})
+	}
+	return rows, page
+}
+
+func encodeCursor(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	// UseNumber supaya id besar tidak berubah jadi float
+	var c Cursor
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	// nilai cursor dari client dipakai sebagai bind variable, hanya string dan angka yang diterima
+	for _, v := range c.Values {
+		switch v.(type) {
+		case string, json.Number:
+		default:
+			return nil, ErrInvalidCursor
+		}
+	}
+	return &c, nil
+}
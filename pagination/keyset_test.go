@@ -0,0 +1,35 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestNewParamsCursor(t *testing.T) {
+	keys := []Key{{Expr: "name"}}
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		cursor string
+		valid  bool
+	}{
+		{"", true},
+		{encodeCursor(Cursor{Sort: "name", Values: []interface{}{"Budi", 7}}), true},
+		{raw(`{"s":"name","v":["Budi",12345678901234567]}`), true},
+		{"bukan-base64!", false},
+		{raw(`{"s":"name","v":`), false},
+		// urutan atau jumlah nilai tidak sama dengan sort yang diminta
+		{encodeCursor(Cursor{Sort: "-name", Values: []interface{}{"Budi", 7}}), false},
+		{encodeCursor(Cursor{Sort: "name", Values: []interface{}{7}}), false},
+		// hanya string dan angka yang boleh jadi bind variable
+		{raw(`{"s":"name","v":[["Budi"],7]}`), false},
+		{raw(`{"s":"name","v":[{"a":1},7]}`), false},
+		{raw(`{"s":"name","v":[null,7]}`), false},
+		{raw(`{"s":"name","v":[true,7]}`), false},
+	}
+	for _, tt := range tests {
+		_, err := NewParams(keys, 10, tt.cursor)
+		if (err == nil) != tt.valid {
+			t.Errorf("%q: error %v, valid %v", tt.cursor, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package phone
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// CountryCodeID kode negara Indonesia
+const CountryCodeID = "62"
+
+var ErrInvalid = errors.New("phone number must be a valid Indonesian number (e.g. 0812..., +62812...) or an E.164 number")
+
+// Normalize mengubah nomor telepon ke format E.164. Nomor Indonesia boleh ditulis dengan awalan
+// 0, 62, +62 atau langsung 8 (nomor seluler), spasi, titik, tanda minus dan kurung diabaikan.
+// Nomor negara lain harus diawali + dengan kode negaranya
+func Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+
+	international := strings.HasPrefix(raw, "+") || strings.HasPrefix(raw, "00")
+	var sb strings.Builder
+	for i, r := range raw {
+		switch {
+		case unicode.IsDigit(r):
+			sb.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalid
+		}
+	}
+	digits := sb.String()
+	if strings.HasPrefix(raw, "00") {
+		digits = digits[2:]
+	}
+
+	var national string
+	switch {
+	case strings.HasPrefix(digits, CountryCodeID):
+		national = digits[len(CountryCodeID):]
+	case international:
+		// nomor luar negeri cukup dicek panjangnya, E.164 maksimal 15 digit
+		if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+			return "", ErrInvalid
+		}
+		return "+" + digits, nil
+	case strings.HasPrefix(digits, "0"):
+		national = digits[1:]
+	case strings.HasPrefix(digits, "8"):
+		national = digits
+	default:
+		return "", ErrInvalid
+	}
+
+	// nomor nasional Indonesia 7-12 digit (telepon rumah 7-10 digit termasuk kode area, seluler 9-12 digit)
+	if len(national) < 7 || len(national) > 12 || national[0] == '0' {
+		return "", ErrInvalid
+	}
+	if national[0] == '8' && len(national) < 9 {
+		return "", ErrInvalid
+	}
+	return "+" + CountryCodeID + national, nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "io"
+
+// AttachmentFile isi file yang disimpan sebagai lampiran. Variants hasil pipeline gambar
+// (nama variant ke isi file) dan disimpan dengan format yang sama seperti file asli
+type AttachmentFile struct {
+	Name        string
+	ContentType string
+	Size        int64
+	Reader      io.Reader
+	Variants    map[string][]byte
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "errors"
+
+// ErrInUse data tidak bisa dihapus karena masih dipakai data lain
+var ErrInUse = errors.New("data is still in use")
+
+// ErrAttachmentOrder urutan baru lampiran tidak berisi semua lampiran data tepat satu kali
+var ErrAttachmentOrder = errors.New("attachment_ids must list every attachment of the record exactly once")
@@ -1,35 +1,125 @@
 package repository
 
 import (
+	"context"
+	"ginDatabaseMhs/audit"
 	"ginDatabaseMhs/model/entity"
-	"io"
-	"mime/multipart"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/pagination"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
 type MahasiswaRepository interface {
-	GetAllUsers() ([]entity.User, error)
+	GetAllUsers(where clause.Expression, page pagination.Params) ([]entity.User, error)
+	GetUsersByIDs(ids []int64) ([]entity.User, error)
 	DeleteUserByIDAndRole(userID int64, role string) error
+	UpdateUserRole(userID int64, role string) (*entity.User, error)
+	GetAllRecords(adminID int64, ownerIDs []int64, filter request.MahasiswaFilter, page pagination.Params, proj request.Projection) ([]entity.User_data, error)
+	CountAllRecords(adminID int64, ownerIDs []int64, filter request.MahasiswaFilter) (int64, error)
+	GetRecordByID(mhsID int64) (*entity.User_data, error)
+	ReassignOwner(mhsID, ownerID int64, actor audit.Actor) (*entity.User_data, error)
 
 	// ALL //////////////////////////////////////////////////////////////////////////////////////////////////////////////////
-	GetAllUserByID(UserID int64) ([]entity.User_data, error)
+	GetAllUserByID(UserID int64, filter request.MahasiswaFilter, page pagination.Params, proj request.Projection) ([]entity.User_data, error)
+	CountByUser(userID int64, filter request.MahasiswaFilter) (int64, error)
+	CountGroupedByUser(userID int64, filter request.MahasiswaFilter, expr string) ([]entity.GroupCount, error)
 	GetByID(mhsID, userID int64) (*entity.User_data, error)
-	Create(mahasiswa *entity.User_data) (*entity.User_data, error)
+	GetByIDWithTrashed(mhsID, userID int64) (*entity.User_data, error)
+	GetProjectedByID(mhsID, userID int64, proj request.Projection) (*entity.User_data, error)
+	GetByIDs(ids []int64, userID int64, proj request.Projection) ([]entity.User_data, error)
+	Create(mahasiswa *entity.User_data, actor audit.Actor) (*entity.User_data, error)
+	CreateBatch(data []entity.User_data, actor audit.Actor) error
 	GetMahasiswaByNameAndEmail(name, email string) (*entity.User_data, error)
-	Update(mhsID, userID int64, updates map[string]interface{}) (*entity.User_data, error)
+	Update(mhsID, userID int64, updates map[string]interface{}, actor audit.Actor) (*entity.User_data, error)
+	Revert(mhsID, userID int64, updates map[string]interface{}, actor audit.Actor) (*entity.User_data, error)
 	UpdatetoAtch(todo *entity.User_data) error
 	CreateAdmin(admin *entity.Admin) error
-	Delete(mhsID, userID int64) (int64, error)
+	Delete(mhsID, userID int64, actor audit.Actor) (int64, error)
+	GetTrashByUser(userID int64, page pagination.Params) ([]entity.User_data, error)
+	GetTrashedByID(mhsID, userID int64) (*entity.User_data, error)
+	Restore(mhsID, userID int64, actor audit.Actor) (int64, error)
+	Purge(mhsID, userID int64, actor audit.Actor) (*entity.User_data, error)
+	PurgeDeletedBefore(before time.Time, actor audit.Actor) ([]entity.User_data, error)
+	MergeMahasiswa(survivorID, duplicateID, userID int64, updates map[string]interface{}, actor audit.Actor) (*entity.User_data, error)
+	SaveShare(share *entity.RecordShare) error
+	DeleteShare(recordID, userID int64) (int64, error)
+	GetShare(recordID, userID int64) (*entity.RecordShare, error)
+	GetSharesByRecord(recordID int64) ([]entity.RecordShare, error)
+	GetSharedWithUser(userID int64, page pagination.Params) ([]entity.RecordShare, error)
+	CreateCourse(course *entity.Course) error
+	GetCoursesByUser(userID int64, page pagination.Params) ([]entity.Course, error)
+	GetCourseByID(courseID, userID int64) (*entity.Course, error)
+	GetCourseByCode(userID int64, code string) (*entity.Course, error)
+	UpdateCourse(courseID, userID int64, updates map[string]interface{}) (int64, error)
+	DeleteCourse(courseID, userID int64) (int64, error)
+	CreateSemester(semester *entity.Semester) error
+	GetSemestersByUser(userID int64, page pagination.Params) ([]entity.Semester, error)
+	GetSemesterByID(semesterID, userID int64) (*entity.Semester, error)
+	UpdateSemester(semesterID, userID int64, updates map[string]interface{}) (int64, error)
+	DeleteSemester(semesterID, userID int64) (int64, error)
+	CreateEnrollment(enrollment *entity.Enrollment) error
+	GetEnrollmentsByRecord(recordID int64) ([]entity.Enrollment, error)
+	GetEnrollmentByID(enrollmentID int64) (*entity.Enrollment, error)
+	DeleteEnrollment(enrollmentID int64) (int64, error)
+	SaveGrade(grade *entity.Grade) error
+	DeleteGrade(enrollmentID int64) (int64, error)
+	CreateClassSession(session *entity.ClassSession) error
+	GetClassSessions(userID, courseID, semesterID int64, page pagination.Params) ([]entity.ClassSession, error)
+	GetClassSessionByID(sessionID, userID int64) (*entity.ClassSession, error)
+	GetClassSessionByMeeting(courseID, semesterID int64, meetingNumber int) (*entity.ClassSession, error)
+	UpdateClassSession(sessionID, userID int64, updates map[string]interface{}) (int64, error)
+	DeleteClassSession(sessionID, userID int64) (int64, error)
+	GetEnrolledRecordIDs(ownerID, courseID, semesterID int64) ([]int64, error)
+	SaveAttendances(attendances []entity.Attendance) error
+	GetAttendanceBySession(sessionID int64) ([]entity.Attendance, error)
+	GetAttendanceStats(courseID, semesterID, recordID int64) ([]entity.AttendanceStat, error)
+	CreateTag(tag *entity.Tag) error
+	GetTagsByUser(userID int64, page pagination.Params) ([]entity.Tag, error)
+	GetTagByID(tagID, userID int64) (*entity.Tag, error)
+	GetTagByName(userID *int64, name string) (*entity.Tag, error)
+	GetTagsByIDs(ids []int64, userID int64) ([]entity.Tag, error)
+	UpdateTag(tagID int64, updates map[string]interface{}) (int64, error)
+	DeleteTag(tagID int64) (int64, error)
+	GetEditableRecordIDs(ids []int64, userID int64) ([]int64, error)
+	AddRecordTags(recordIDs, tagIDs []int64) (int64, error)
+	RemoveRecordTags(recordIDs, tagIDs []int64) (int64, error)
+	CreateCustomField(field *entity.CustomField) error
+	GetCustomFieldsByUser(userID int64) ([]entity.CustomField, error)
+	GetCustomFieldByID(fieldID int64) (*entity.CustomField, error)
+	GetCustomFieldByKey(userID int64, key string) (*entity.CustomField, error)
+	UpdateCustomField(fieldID, userID int64, updates map[string]interface{}) (int64, error)
+	DeleteCustomField(fieldID, userID int64) (int64, error)
+	CreateComment(comment *entity.Comment) error
+	GetCommentsByRecord(recordID, viewerID int64, page pagination.Params) ([]entity.Comment, error)
+	GetCommentByID(commentID int64) (*entity.Comment, error)
+	UpdateComment(comment *entity.Comment, body, visibility string, editorID int64) error
+	DeleteComment(commentID int64) (int64, error)
+	GetCommentRevisions(commentID int64) ([]entity.CommentRevision, error)
 	CreateUser(user *entity.User) error
 	GetUserByUsernameOrEmail(username, email string) (*entity.User, error)
 	//UploadTodoFileS3(file *multipart.FileHeader, url string) error
 	//UploadTodoFileLocal(file *multipart.FileHeader, url string) error
 	/////////////////////
-	CreateAttachment(mhsID int64, path string, order int64) (*entity.Attachment, error)
-	UploadFileS3Atch(file *multipart.FileHeader, mhsID, userID int64) (*entity.Attachment, error)
-	UpdateWithAttachments(mhs *entity.User_data) error
-	UploadFileS3Buckets(file io.Reader, fileName string) (*string, error)
-	UploadFileLocalAtch(file *multipart.FileHeader, mhsID, userID int64) (*entity.Attachment, error)
-	SearchMahasiswaByUser(userID int64, search string, page, perPage int) ([]entity.User_data, int64, error)
+	CreateAttachment(mhsID int64, path string, order int64, actor audit.Actor) (*entity.Attachment, error)
+	GetAttachmentByID(id int64) (*entity.Attachment, error)
+	GetAttachmentsByRecord(mhsID int64) ([]entity.Attachment, error)
+	DeleteAttachment(ctx context.Context, id int64, actor audit.Actor) (*entity.Attachment, error)
+	ReplaceAttachmentFile(ctx context.Context, attachment *entity.Attachment, file AttachmentFile, actor audit.Actor) (*entity.Attachment, error)
+	ReorderAttachments(mhsID int64, ids []int64, actor audit.Actor) ([]entity.Attachment, error)
+	CreatePendingUpload(upload *entity.PendingUpload) error
+	GetPendingUpload(key string, userID int64) (*entity.PendingUpload, error)
+	DeletePendingUpload(id int64) error
+	CompletePendingUpload(ctx context.Context, upload *entity.PendingUpload, file *AttachmentFile, actor audit.Actor) (*entity.Attachment, error)
+	PurgeExpiredUploads(before time.Time) ([]entity.PendingUpload, error)
+	SaveAttachment(ctx context.Context, mhsID, userID int64, file AttachmentFile, actor audit.Actor) (*entity.Attachment, error)
+	UpdateWithAttachments(mhs *entity.User_data, actor audit.Actor) error
+	SearchMahasiswaByUser(userID int64, filter request.MahasiswaFilter, sort []request.SortField, page, perPage int, proj request.Projection) ([]entity.User_data, int64, error)
+	StreamAll(fn func(entity.User_data) error) error
+	StreamByUser(userID int64, filter request.MahasiswaFilter, columns []string, fn func(entity.User_data) error) error
+	// HISTORY //////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	GetAuditLogsByRecord(recordID int64, page pagination.Params) ([]entity.AuditLog, error)
+	GetAuditLogVersion(entityType string, entityID int64, version int) (*entity.AuditLog, error)
 	//
 	GetRoleByName(roleName string) (*entity.Roles, error)
 }
@@ -17,25 +17,93 @@ func NewRouteBuilder(dataService *mhsService.Handler) *RouteBuilder {
 func (rb *RouteBuilder) RouteInit() *gin.Engine {
 
 	r := gin.New()
-	r.Use(middleware.RecoveryMiddleware(), middleware.Logger())
+	r.Use(middleware.RecoveryMiddleware(), middleware.RequestID(), middleware.Logger())
 	//r.Use(gin.Recovery(), middleware.Logger(), middleware.BasicAuth())
 
 	auth := r.Group("/", middleware.Authmiddleware())
+	admin := auth.Group("/admin", middleware.AdminOnly())
+	{
+		admin.GET("/viewUsers", rb.dataService.ViewAllUsers)
+		admin.DELETE("/users/:user_id", rb.dataService.DeleteUser)
+		admin.PUT("/users/:user_id/role", rb.dataService.AdminUserRole)
+		admin.GET("/records", rb.dataService.AdminRecordList)
+		admin.GET("/records/:id", rb.dataService.AdminRecordGet)
+		admin.PUT("/records/:id/owner", rb.dataService.AdminRecordReassign)
+	}
 	{
-		auth.GET("/admin/viewUsers", rb.dataService.ViewAllUsers)
-		auth.DELETE("admin/users/:user_id", rb.dataService.DeleteUser)
 		auth.GET("/manage-data", rb.dataService.HandlerGetAll)
 		auth.GET("/access", rb.dataService.Access)
 		auth.POST("/create-form", rb.dataService.HandlerCreate)
+		auth.POST("/manage-data/import", rb.dataService.ImportHandler)
+		auth.GET("/manage-data/export", rb.dataService.ExportHandler)
+		auth.GET("/manage-data/stats", rb.dataService.StatsHandler)
+		auth.GET("/manage-data/duplicates", rb.dataService.DuplicatesHandler)
+		auth.POST("/manage-data/merge", rb.dataService.MergeHandler)
 		auth.GET("/manage-data/daftarMahasiswa/:id", rb.dataService.HandlerGetByID)
 		auth.PUT("/manage-data/daftarMahasiswa/:id", rb.dataService.HandlerUpdate)
 		auth.DELETE("/manage-data/daftarMahasiswa/:id", rb.dataService.HandlerDelete)
-		auth.POST("/uploadS3/:id", rb.dataService.UploadFileS3AtchHandler)
-		auth.POST("/uploadLocal/:id", rb.dataService.UploadLocalAtchHandler)
+		auth.GET("/manage-data/daftarMahasiswa/:id/history", rb.dataService.HistoryHandler)
+		auth.POST("/manage-data/daftarMahasiswa/:id/history/:version/revert", rb.dataService.RevertHandler)
+		auth.GET("/manage-data/daftarMahasiswa/:id/shares", rb.dataService.ShareListHandler)
+		auth.PUT("/manage-data/daftarMahasiswa/:id/shares", rb.dataService.ShareGrantHandler)
+		auth.DELETE("/manage-data/daftarMahasiswa/:id/shares/:user_id", rb.dataService.ShareRevokeHandler)
+		auth.GET("/shared-with-me", rb.dataService.SharedWithMeHandler)
+		auth.GET("/manage-data/daftarMahasiswa/:id/enrollments", rb.dataService.EnrollmentList)
+		auth.POST("/manage-data/daftarMahasiswa/:id/enrollments", rb.dataService.EnrollmentCreate)
+		auth.GET("/manage-data/daftarMahasiswa/:id/gpa", rb.dataService.GPAHandler)
+		auth.DELETE("/enrollments/:id", rb.dataService.EnrollmentDelete)
+		auth.PUT("/enrollments/:id/grade", rb.dataService.GradeSave)
+		auth.DELETE("/enrollments/:id/grade", rb.dataService.GradeDelete)
+		auth.GET("/courses", rb.dataService.CourseList)
+		auth.POST("/courses", rb.dataService.CourseCreate)
+		auth.GET("/courses/:id", rb.dataService.CourseGet)
+		auth.PUT("/courses/:id", rb.dataService.CourseUpdate)
+		auth.DELETE("/courses/:id", rb.dataService.CourseDelete)
+		auth.GET("/semesters", rb.dataService.SemesterList)
+		auth.POST("/semesters", rb.dataService.SemesterCreate)
+		auth.GET("/semesters/:id", rb.dataService.SemesterGet)
+		auth.PUT("/semesters/:id", rb.dataService.SemesterUpdate)
+		auth.DELETE("/semesters/:id", rb.dataService.SemesterDelete)
+		auth.GET("/class-sessions", rb.dataService.ClassSessionList)
+		auth.POST("/class-sessions", rb.dataService.ClassSessionCreate)
+		auth.GET("/class-sessions/:id", rb.dataService.ClassSessionGet)
+		auth.PUT("/class-sessions/:id", rb.dataService.ClassSessionUpdate)
+		auth.DELETE("/class-sessions/:id", rb.dataService.ClassSessionDelete)
+		auth.GET("/class-sessions/:id/attendance", rb.dataService.AttendanceList)
+		auth.PUT("/class-sessions/:id/attendance", rb.dataService.AttendanceSubmit)
+		auth.GET("/attendance/report", rb.dataService.AttendanceCourseReport)
+		auth.GET("/manage-data/daftarMahasiswa/:id/attendance", rb.dataService.AttendanceStudentReport)
+		auth.GET("/manage-data/daftarMahasiswa/:id/comments", rb.dataService.CommentList)
+		auth.POST("/manage-data/daftarMahasiswa/:id/comments", rb.dataService.CommentCreate)
+		auth.GET("/comments/:id", rb.dataService.CommentGet)
+		auth.PUT("/comments/:id", rb.dataService.CommentUpdate)
+		auth.DELETE("/comments/:id", rb.dataService.CommentDelete)
+		auth.GET("/comments/:id/history", rb.dataService.CommentHistory)
+		auth.GET("/tags", rb.dataService.TagList)
+		auth.POST("/tags", rb.dataService.TagCreate)
+		auth.PUT("/tags/:id", rb.dataService.TagUpdate)
+		auth.DELETE("/tags/:id", rb.dataService.TagDelete)
+		auth.POST("/manage-data/tag", rb.dataService.TagRecordsHandler)
+		auth.POST("/manage-data/untag", rb.dataService.UntagRecordsHandler)
+		auth.GET("/custom-fields", rb.dataService.CustomFieldList)
+		auth.POST("/custom-fields", rb.dataService.CustomFieldCreate)
+		auth.PUT("/custom-fields/:id", rb.dataService.CustomFieldUpdate)
+		auth.DELETE("/custom-fields/:id", rb.dataService.CustomFieldDelete)
+		auth.POST("/attachments", rb.dataService.AttachmentUpload)
+		auth.POST("/attachments/presign", rb.dataService.AttachmentPresignUpload)
+		auth.POST("/attachments/presign/complete", rb.dataService.AttachmentPresignComplete)
+		auth.GET("/attachments/:id", rb.dataService.AttachmentDownload)
+		auth.GET("/attachments/:id/presign", rb.dataService.AttachmentPresignDownload)
+		auth.PUT("/attachments/:id/file", rb.dataService.AttachmentReplace)
+		auth.DELETE("/attachments/:id", rb.dataService.AttachmentDelete)
+		auth.PUT("/manage-data/daftarMahasiswa/:id/attachments/order", rb.dataService.AttachmentReorder)
 		auth.GET("/list-Search", rb.dataService.SearchHandler)
+		auth.GET("/list-Search/fuzzy", rb.dataService.FuzzySearchHandler)
+		auth.GET("/trash", rb.dataService.TrashList)
+		auth.POST("/trash/:id/restore", rb.dataService.TrashRestore)
+		auth.DELETE("/trash/:id", rb.dataService.TrashPurge)
 	}
 
-	r.POST("/uploadBuckets", rb.dataService.UploadFileS3BucketsHandler)
 	r.POST("/register", rb.dataService.Register)
 	r.POST("/login", rb.dataService.Login)
 	return r
@@ -0,0 +1,50 @@
+package rsql
+
+import "fmt"
+
+// Node bagian dari hasil parsing ekspresi filter
+type Node interface {
+	node()
+}
+
+// Logical gabungan beberapa node dengan AND (;) atau OR (,)
+type Logical struct {
+	Op       string
+	Children []Node
+}
+
+// Comparison satu perbandingan, contoh age=ge=20
+type Comparison struct {
+	Field    string
+	Operator string
+	Args     []Argument
+	Pos      int
+}
+
+// Argument nilai perbandingan beserta posisinya di ekspresi
+type Argument struct {
+	Value string
+	Pos   int
+}
+
+const (
+	OpAnd = "and"
+	OpOr  = "or"
+)
+
+func (Logical) node()    {}
+func (Comparison) node() {}
+
+// Error kesalahan parsing atau kompilasi, Pos adalah posisi karakter (mulai dari 0)
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.Pos, e.Msg)
+}
+
+func errorAt(pos int, format string, args ...interface{}) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,218 @@
+package rsql
+
+import (
+	"gorm.io/gorm/clause"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType menentukan bagaimana nilai argumen dikonversi
+type FieldType int
+
+const (
+	String FieldType = iota
+	Int
+	Date
+	Float
+	Bool
+)
+
+var (
+	StringOperators = []string{"==", "!=", "=like=", "=in=", "=out=", "=isnull="}
+	OrderOperators  = []string{"==", "!=", "=lt=", "=le=", "=gt=", "=ge=", "=in=", "=out=", "=isnull="}
+	BoolOperators   = []string{"==", "!=", "=isnull="}
+)
+
+// Field kolom yang boleh dipakai di filter beserta operator yang diizinkan
+type Field struct {
+	Column    string
+	Type      FieldType
+	Operators []string
+}
+
+// Schema whitelist field untuk satu entity, key adalah nama field di ekspresi
+type Schema map[string]Field
+
+// alias operator FIQL ke bentuk =name=
+var operatorAliases = map[string]string{
+	"<":  "=lt=",
+	"<=": "=le=",
+	">":  "=gt=",
+	">=": "=ge=",
+}
+
+var comparisonSQL = map[string]string{
+	"==":   "=",
+	"!=":   "<>",
+	"=lt=": "<",
+	"=le=": "<=",
+	"=gt=": ">",
+	"=ge=": ">=",
+}
+
+// Compile mengubah AST menjadi kondisi WHERE dengan parameter, hanya field di schema yang boleh dipakai
+func (s Schema) Compile(node Node) (clause.Expression, error) {
+	sql, vars, err := s.compile(node)
+	if err != nil {
+		return nil, err
+	}
+	return clause.Expr{SQL: sql, Vars: vars}, nil
+}
+
+// ParseAndCompile gabungan Parse dan Compile
+func (s Schema) ParseAndCompile(input string) (clause.Expression, error) {
+	node, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return s.Compile(node)
+}
+
+func (s Schema) compile(node Node) (string, []interface{}, error) {
+	switch n := node.(type) {
+	case Logical:
+		joiner := " AND "
+		if n.Op == OpOr {
+			joiner = " OR "
+		}
+
+		parts := make([]string, 0, len(n.Children))
+		var vars []interface{}
+		for _, child := range n.Children {
+			sql, childVars, err := s.compile(child)
+			if err != nil {
+				return "", nil, err
+			}
+			parts = append(parts, "("+sql+")")
+			vars = append(vars, childVars...)
+		}
+		return strings.Join(parts, joiner), vars, nil
+	case Comparison:
+		return s.compileComparison(n)
+	}
+	return "", nil, errorAt(0, "unsupported expression")
+}
+
+func (s Schema) compileComparison(c Comparison) (string, []interface{}, error) {
+	field, ok := s[c.Field]
+	if !ok {
+		return "", nil, errorAt(c.Pos, "unknown field %q, allowed: %s", c.Field, strings.Join(s.fieldNames(), ", "))
+	}
+
+	operator := c.Operator
+	if alias, ok := operatorAliases[operator]; ok {
+		operator = alias
+	}
+	if !contains(field.Operators, operator) {
+		return "", nil, errorAt(c.Pos, "operator %s is not allowed for field %q", c.Operator, c.Field)
+	}
+
+	multiple := operator == "=in=" || operator == "=out="
+	if !multiple && len(c.Args) != 1 {
+		return "", nil, errorAt(c.Pos, "operator %s expects a single value", c.Operator)
+	}
+
+	column := field.Column
+	switch operator {
+	case "=isnull=":
+		isNull, err := strconv.ParseBool(c.Args[0].Value)
+		if err != nil {
+			return "", nil, errorAt(c.Args[0].Pos, "=isnull= expects true or false")
+		}
+		if isNull {
+			return column + " IS NULL", nil, nil
+		}
+		return column + " IS NOT NULL", nil, nil
+	case "=like=":
+		return column + " LIKE ?", []interface{}{likePattern(c.Args[0].Value)}, nil
+	case "=in=", "=out=":
+		values := make([]interface{}, 0, len(c.Args))
+		for _, arg := range c.Args {
+			v, err := convert(field.Type, arg)
+			if err != nil {
+				return "", nil, err
+			}
+			values = append(values, v)
+		}
+		if operator == "=out=" {
+			return column + " NOT IN ?", []interface{}{values}, nil
+		}
+		return column + " IN ?", []interface{}{values}, nil
+	}
+
+	// == dengan wildcard * pada field string berarti LIKE
+	if field.Type == String && strings.Contains(c.Args[0].Value, "*") && (operator == "==" || operator == "!=") {
+		if operator == "!=" {
+			return column + " NOT LIKE ?", []interface{}{likePattern(c.Args[0].Value)}, nil
+		}
+		return column + " LIKE ?", []interface{}{likePattern(c.Args[0].Value)}, nil
+	}
+
+	v, err := convert(field.Type, c.Args[0])
+	if err != nil {
+		return "", nil, err
+	}
+	return column + " " + comparisonSQL[operator] + " ?", []interface{}{v}, nil
+}
+
+func (s Schema) fieldNames() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func convert(t FieldType, arg Argument) (interface{}, error) {
+	switch t {
+	case Int:
+		n, err := strconv.ParseInt(arg.Value, 10, 64)
+		if err != nil {
+			return nil, errorAt(arg.Pos, "%q is not a number", arg.Value)
+		}
+		return n, nil
+	case Float:
+		n, err := strconv.ParseFloat(arg.Value, 64)
+		if err != nil {
+			return nil, errorAt(arg.Pos, "%q is not a number", arg.Value)
+		}
+		return n, nil
+	case Bool:
+		// dibandingkan sebagai teks karena nilai boolean dari kolom JSON dibaca sebagai 'true' / 'false'
+		b, err := strconv.ParseBool(arg.Value)
+		if err != nil {
+			return nil, errorAt(arg.Pos, "%q is not true or false", arg.Value)
+		}
+		return strconv.FormatBool(b), nil
+	case Date:
+		if _, err := time.Parse("2006-01-02", arg.Value); err != nil {
+			return nil, errorAt(arg.Pos, "%q is not a date in YYYY-MM-DD format", arg.Value)
+		}
+		return arg.Value, nil
+	}
+	return arg.Value, nil
+}
+
+// likePattern mengubah wildcard * menjadi % dan meng-escape karakter LIKE lainnya
+func likePattern(value string) string {
+	return strings.ReplaceAll(EscapeLike(value), "*", "%")
+}
+
+// EscapeLike meng-escape \, % dan _ dengan backslash supaya value dicocokkan apa adanya di LIKE
+func EscapeLike(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "%", `\%`)
+	return strings.ReplaceAll(value, "_", `\_`)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
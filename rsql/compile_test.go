@@ -0,0 +1,92 @@
+package rsql
+
+import (
+	"errors"
+	"gorm.io/gorm/clause"
+	"reflect"
+	"testing"
+)
+
+var testSchema = Schema{
+	"name":      {Column: "name", Type: String, Operators: StringOperators},
+	"age":       {Column: "age", Type: Int, Operators: OrderOperators},
+	"birthdate": {Column: "birthdate", Type: Date, Operators: OrderOperators},
+	"gpa":       {Column: "gpa", Type: Float, Operators: OrderOperators},
+	"active":    {Column: "active", Type: Bool, Operators: BoolOperators},
+}
+
+func TestParseAndCompile(t *testing.T) {
+	tests := []struct {
+		input string
+		sql   string
+		vars  []interface{}
+	}{
+		{"name==Budi", "name = ?", []interface{}{"Budi"}},
+		{"name!='Budi Santoso'", "name <> ?", []interface{}{"Budi Santoso"}},
+		{`name=="say \"hi\""`, "name = ?", []interface{}{`say "hi"`}},
+		{"age=gt=20", "age > ?", []interface{}{int64(20)}},
+		{"age>=20", "age >= ?", []interface{}{int64(20)}},
+		{"age<30", "age < ?", []interface{}{int64(30)}},
+		{"gpa=le=3.5", "gpa <= ?", []interface{}{3.5}},
+		{"birthdate=ge=2000-01-01", "birthdate >= ?", []interface{}{"2000-01-01"}},
+		{"active==TRUE", "active = ?", []interface{}{"true"}},
+		{"name=isnull=true", "name IS NULL", nil},
+		{"name=isnull=false", "name IS NOT NULL", nil},
+		{"age=in=(20,21)", "age IN ?", []interface{}{[]interface{}{int64(20), int64(21)}}},
+		{"name=out=(a,b)", "name NOT IN ?", []interface{}{[]interface{}{"a", "b"}}},
+		{"name=like=bu*", "name LIKE ?", []interface{}{"bu%"}},
+		{"name==*50%_off*", "name LIKE ?", []interface{}{`%50\%\_off%`}},
+		{"name!=bu*", "name NOT LIKE ?", []interface{}{"bu%"}},
+		{"name==Budi;age=gt=20", "(name = ?) AND (age > ?)", []interface{}{"Budi", int64(20)}},
+		{"name==a,name==b", "(name = ?) OR (name = ?)", []interface{}{"a", "b"}},
+		{"age<20,(name==a;age>30)", "(age < ?) OR ((name = ?) AND (age > ?))", []interface{}{int64(20), "a", int64(30)}},
+		{" name==Budi ", "name = ?", []interface{}{"Budi"}},
+	}
+	for _, tt := range tests {
+		expr, err := testSchema.ParseAndCompile(tt.input)
+		if err != nil {
+			t.Errorf("%q: %v", tt.input, err)
+			continue
+		}
+		got := expr.(clause.Expr)
+		if got.SQL != tt.sql || !reflect.DeepEqual(got.Vars, tt.vars) {
+			t.Errorf("%q: got %q %#v, want %q %#v", tt.input, got.SQL, got.Vars, tt.sql, tt.vars)
+		}
+	}
+}
+
+func TestParseAndCompileErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+	}{
+		{"", 0},
+		{"   ", 0},
+		{"email==a@b.c", 0},
+		{"age=like=2*", 0},
+		{"active=gt=true", 0},
+		{"age==(1,2)", 0},
+		{"age==abc", 5},
+		{"gpa==x", 5},
+		{"active==maybe", 8},
+		{"birthdate==01-01-2000", 11},
+		{"name=isnull=maybe", 12},
+		{"name==Budi;", 11},
+		{"name==Budi)", 10},
+		{"(name==Budi", 0},
+		{"name == Budi", 4},
+		{"name=='Budi", 6},
+		{"name=~Budi", 4},
+	}
+	for _, tt := range tests {
+		_, err := testSchema.ParseAndCompile(tt.input)
+		var rerr *Error
+		if !errors.As(err, &rerr) {
+			t.Errorf("%q: error %v, want *rsql.Error", tt.input, err)
+			continue
+		}
+		if rerr.Pos != tt.pos {
+			t.Errorf("%q: error at %d (%v), want %d", tt.input, rerr.Pos, rerr, tt.pos)
+		}
+	}
+}
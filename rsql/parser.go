@@ -0,0 +1,245 @@
+package rsql
+
+import "strings"
+
+// karakter yang tidak boleh muncul di nilai tanpa tanda kutip
+const reserved = "\"'();,=!~<> "
+
+// Parse mengubah ekspresi RSQL/FIQL menjadi AST.
+//
+//	expression  = or
+//	or          = and { "," and }
+//	and         = constraint { ";" constraint }
+//	constraint  = "(" or ")" | comparison
+//	comparison  = selector operator argument
+//	operator    = "==" | "!=" | "<" | "<=" | ">" | ">=" | "=" name "="
+//	argument    = value | "(" value { "," value } ")"
+func Parse(input string) (Node, error) {
+	p := &parser{input: input}
+	p.skipSpace()
+	if p.eof() {
+		return nil, errorAt(0, "empty expression")
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if !p.eof() {
+		return nil, errorAt(p.pos, "unexpected %q", p.input[p.pos])
+	}
+	return node, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *parser) parseOr() (Node, error) {
+	return p.parseLogical(OpOr, ',', p.parseAnd)
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	return p.parseLogical(OpAnd, ';', p.parseConstraint)
+}
+
+func (p *parser) parseLogical(op string, sep byte, next func() (Node, error)) (Node, error) {
+	first, err := next()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Node{first}
+	for {
+		p.skipSpace()
+		if p.peek() != sep {
+			break
+		}
+		p.pos++
+		child, err := next()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return Logical{Op: op, Children: children}, nil
+}
+
+func (p *parser) parseConstraint() (Node, error) {
+	p.skipSpace()
+	if p.peek() != '(' {
+		return p.parseComparison()
+	}
+
+	open := p.pos
+	p.pos++
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.peek() != ')' {
+		if p.eof() {
+			return nil, errorAt(open, "unclosed parenthesis")
+		}
+		return nil, errorAt(p.pos, "expected ')' but found %q", p.peek())
+	}
+	p.pos++
+	return node, nil
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	p.skipSpace()
+	start := p.pos
+	for !p.eof() && isSelectorChar(p.peek()) {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.eof() {
+			return nil, errorAt(p.pos, "expected field name but found end of expression")
+		}
+		return nil, errorAt(p.pos, "expected field name but found %q", p.peek())
+	}
+	field := p.input[start:p.pos]
+
+	operator, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := p.parseArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	return Comparison{Field: field, Operator: operator, Args: args, Pos: start}, nil
+}
+
+func (p *parser) parseOperator() (string, error) {
+	start := p.pos
+	rest := p.input[p.pos:]
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(rest, op) {
+			p.pos += len(op)
+			return op, nil
+		}
+	}
+
+	if p.peek() != '=' {
+		if p.eof() {
+			return "", errorAt(p.pos, "expected operator but found end of expression")
+		}
+		return "", errorAt(p.pos, "expected operator but found %q", p.peek())
+	}
+	p.pos++
+	for !p.eof() && isLetter(p.peek()) {
+		p.pos++
+	}
+	if p.peek() != '=' || p.pos == start+1 {
+		return "", errorAt(start, "malformed operator, expected form =name=")
+	}
+	p.pos++
+	return strings.ToLower(p.input[start:p.pos]), nil
+}
+
+func (p *parser) parseArguments() ([]Argument, error) {
+	if p.peek() != '(' {
+		arg, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return []Argument{arg}, nil
+	}
+
+	open := p.pos
+	p.pos++
+	var args []Argument
+	for {
+		p.skipSpace()
+		arg, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return args, nil
+		case 0:
+			return nil, errorAt(open, "unclosed argument list")
+		default:
+			return nil, errorAt(p.pos, "expected ',' or ')' but found %q", p.peek())
+		}
+	}
+}
+
+func (p *parser) parseValue() (Argument, error) {
+	start := p.pos
+	if quote := p.peek(); quote == '"' || quote == '\'' {
+		p.pos++
+		var sb strings.Builder
+		for {
+			if p.eof() {
+				return Argument{}, errorAt(start, "unterminated quoted value")
+			}
+			c := p.input[p.pos]
+			if c == '\\' && p.pos+1 < len(p.input) {
+				sb.WriteByte(p.input[p.pos+1])
+				p.pos += 2
+				continue
+			}
+			p.pos++
+			if c == quote {
+				return Argument{Value: sb.String(), Pos: start}, nil
+			}
+			sb.WriteByte(c)
+		}
+	}
+
+	for !p.eof() && !strings.ContainsRune(reserved, rune(p.peek())) {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.eof() {
+			return Argument{}, errorAt(p.pos, "expected value but found end of expression")
+		}
+		return Argument{}, errorAt(p.pos, "expected value but found %q", p.peek())
+	}
+	return Argument{Value: p.input[start:p.pos], Pos: start}, nil
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isSelectorChar(c byte) bool {
+	return isLetter(c) || (c >= '0' && c <= '9') || c == '_' || c == '.'
+}
@@ -0,0 +1,54 @@
+package search
+
+import "sync"
+
+// MemoryIndex index di dalam proses, kandidat dicari lewat trigram lalu dinilai dengan fuzzy matching
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[int64]Document
+	trigrams *trigramIndex
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     map[int64]Document{},
+		trigrams: newTrigramIndex(),
+	}
+}
+
+func (m *MemoryIndex) Index(doc Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.docs[doc.ID] = doc
+	m.trigrams.add(doc)
+	return nil
+}
+
+func (m *MemoryIndex) Remove(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.docs, id)
+	m.trigrams.remove(id)
+	return nil
+}
+
+func (m *MemoryIndex) Search(ownerID int64, query string, limit int) ([]Hit, error) {
+	terms := QueryTokens(query)
+	if len(terms) == 0 {
+		return []Hit{}, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// dokumen yang punya minimal satu trigram yang sama menjadi kandidat
+	ids := m.trigrams.candidates(ownerID, terms, 0)
+	candidates := make([]Document, 0, len(ids))
+	for _, id := range ids {
+		candidates = append(candidates, m.docs[id])
+	}
+
+	return rank(terms, candidates, limit), nil
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"ginDatabaseMhs/model/entity"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// jumlah kandidat maksimal dari FULLTEXT sebelum dinilai ulang
+const mysqlCandidateLimit = 200
+
+// MySQLIndex memakai FULLTEXT index di tabel user_data, index selalu sinkron karena dikelola MySQL
+type MySQLIndex struct {
+	DB *gorm.DB
+}
+
+func NewMySQLIndex(db *gorm.DB) *MySQLIndex {
+	return &MySQLIndex{DB: db}
+}
+
+func (m *MySQLIndex) Index(doc Document) error {
+	return nil
+}
+
+func (m *MySQLIndex) Remove(id int64) error {
+	return nil
+}
+
+func (m *MySQLIndex) Search(ownerID int64, query string, limit int) ([]Hit, error) {
+	terms := QueryTokens(query)
+	if len(terms) == 0 {
+		return []Hit{}, nil
+	}
+
+	// boolean mode dengan awalan kata (asli dan yang sudah dinormalisasi) supaya variasi ejaan
+	// ikut jadi kandidat, contoh "Muhamad" mencari "muha*" sehingga "Muhammad" juga ditemukan
+	var parts []string
+	seen := map[string]bool{}
+	for _, t := range tokenize(query) {
+		for _, word := range []string{strings.ToLower(t.text), t.normalized} {
+			prefix := []rune(word)
+			if len(prefix) > 4 {
+				prefix = prefix[:4]
+			}
+			if len(prefix) > 0 && !seen[string(prefix)] {
+				seen[string(prefix)] = true
+				parts = append(parts, string(prefix)+"*")
+			}
+		}
+	}
+
+	var rows []entity.User_data
+	err := m.DB.Model(&entity.User_data{}).
+		Select("id", "user_id", "name", "email", "address", "phone_number").
+		Where("user_id = ?", ownerID).
+		Where("MATCH(name, email, address, phone_number) AGAINST (? IN BOOLEAN MODE)", strings.Join(parts, " ")).
+		Limit(mysqlCandidateLimit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]Document, 0, len(rows))
+	for _, row := range rows {
+		docs = append(docs, DocumentFrom(row))
+	}
+	return rank(terms, docs, limit), nil
+}
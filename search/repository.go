@@ -0,0 +1,142 @@
+package search
+
+import (
+	"ginDatabaseMhs/audit"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/repository"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// IndexedRepository membungkus MahasiswaRepository supaya setiap create, update dan delete
+// ikut memperbarui index pencarian
+type IndexedRepository struct {
+	repository.MahasiswaRepository
+	Index Index
+}
+
+func NewIndexedRepository(repo repository.MahasiswaRepository, index Index) *IndexedRepository {
+	return &IndexedRepository{
+		MahasiswaRepository: repo,
+		Index:               index,
+	}
+}
+
+func (r *IndexedRepository) Create(mahasiswa *entity.User_data, actor audit.Actor) (*entity.User_data, error) {
+	data, err := r.MahasiswaRepository.Create(mahasiswa, actor)
+	if err == nil {
+		r.index(*data)
+	}
+	return data, err
+}
+
+func (r *IndexedRepository) CreateBatch(data []entity.User_data, actor audit.Actor) error {
+	err := r.MahasiswaRepository.CreateBatch(data, actor)
+	if err == nil {
+		for _, d := range data {
+			r.index(d)
+		}
+	}
+	return err
+}
+
+func (r *IndexedRepository) Update(mhsID, userID int64, updates map[string]interface{}, actor audit.Actor) (*entity.User_data, error) {
+	data, err := r.MahasiswaRepository.Update(mhsID, userID, updates, actor)
+	if err == nil {
+		r.reindex(mhsID, userID)
+	}
+	return data, err
+}
+
+func (r *IndexedRepository) Revert(mhsID, userID int64, updates map[string]interface{}, actor audit.Actor) (*entity.User_data, error) {
+	data, err := r.MahasiswaRepository.Revert(mhsID, userID, updates, actor)
+	if err == nil {
+		r.reindex(mhsID, userID)
+	}
+	return data, err
+}
+
+func (r *IndexedRepository) UpdatetoAtch(mhs *entity.User_data) error {
+	err := r.MahasiswaRepository.UpdatetoAtch(mhs)
+	if err == nil {
+		r.index(*mhs)
+	}
+	return err
+}
+
+func (r *IndexedRepository) Delete(mhsID, userID int64, actor audit.Actor) (int64, error) {
+	rowsAffected, err := r.MahasiswaRepository.Delete(mhsID, userID, actor)
+	if err == nil && rowsAffected > 0 {
+		r.remove(mhsID)
+	}
+	return rowsAffected, err
+}
+
+func (r *IndexedRepository) Restore(mhsID, userID int64, actor audit.Actor) (int64, error) {
+	rowsAffected, err := r.MahasiswaRepository.Restore(mhsID, userID, actor)
+	if err == nil && rowsAffected > 0 {
+		r.reindex(mhsID, userID)
+	}
+	return rowsAffected, err
+}
+
+func (r *IndexedRepository) Purge(mhsID, userID int64, actor audit.Actor) (*entity.User_data, error) {
+	data, err := r.MahasiswaRepository.Purge(mhsID, userID, actor)
+	if err == nil && data != nil {
+		r.remove(mhsID)
+	}
+	return data, err
+}
+
+func (r *IndexedRepository) PurgeDeletedBefore(before time.Time, actor audit.Actor) ([]entity.User_data, error) {
+	data, err := r.MahasiswaRepository.PurgeDeletedBefore(before, actor)
+	if err == nil {
+		for _, d := range data {
+			r.remove(d.ID)
+		}
+	}
+	return data, err
+}
+
+func (r *IndexedRepository) MergeMahasiswa(survivorID, duplicateID, userID int64, updates map[string]interface{}, actor audit.Actor) (*entity.User_data, error) {
+	data, err := r.MahasiswaRepository.MergeMahasiswa(survivorID, duplicateID, userID, updates, actor)
+	if err == nil && data != nil {
+		r.remove(duplicateID)
+		r.index(*data)
+	}
+	return data, err
+}
+
+func (r *IndexedRepository) ReassignOwner(mhsID, ownerID int64, actor audit.Actor) (*entity.User_data, error) {
+	data, err := r.MahasiswaRepository.ReassignOwner(mhsID, ownerID, actor)
+	if err == nil && data != nil {
+		r.index(*data)
+	}
+	return data, err
+}
+
+func (r *IndexedRepository) reindex(mhsID, userID int64) {
+	data, err := r.MahasiswaRepository.GetByID(mhsID, userID)
+	if err != nil {
+		logrus.Errorf("failed to reindex data %d: %v", mhsID, err)
+		return
+	}
+	if data == nil {
+		r.remove(mhsID)
+		return
+	}
+	r.index(*data)
+}
+
+// kegagalan index tidak membatalkan perubahan data, cukup di log
+func (r *IndexedRepository) index(data entity.User_data) {
+	if err := r.Index.Index(DocumentFrom(data)); err != nil {
+		logrus.Errorf("failed to index data %d: %v", data.ID, err)
+	}
+}
+
+func (r *IndexedRepository) remove(id int64) {
+	if err := r.Index.Remove(id); err != nil {
+		logrus.Errorf("failed to remove data %d from index: %v", id, err)
+	}
+}
@@ -0,0 +1,261 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// kemiripan minimal supaya sebuah kata dianggap cocok
+const minSimilarity = 0.75
+
+// bobot setiap field saat menghitung relevansi
+var fieldWeights = []struct {
+	name   string
+	weight float64
+	value  func(Document) string
+}{
+	{"name", 3, func(d Document) string { return d.Name }},
+	{"email", 2, func(d Document) string { return d.Email }},
+	{"address", 1, func(d Document) string { return d.Address }},
+	{"phone_number", 1, func(d Document) string { return d.PhoneNumber }},
+}
+
+// ejaan lama dan variasi penulisan nama Indonesia, diterapkan berurutan
+var spellingRules = strings.NewReplacer(
+	"oe", "u",
+	"dj", "j",
+	"tj", "c",
+	"sj", "sy",
+	"nj", "ny",
+	"ch", "kh",
+	"ph", "f",
+)
+
+var diacritics = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+)
+
+type token struct {
+	text       string
+	normalized string
+	start, end int
+}
+
+// tokenize memecah teks menjadi kata beserta posisi byte-nya di teks asli
+func tokenize(s string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range s {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			tokens = append(tokens, newToken(s, start, i))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, newToken(s, start, len(s)))
+	}
+	return tokens
+}
+
+func newToken(s string, start, end int) token {
+	return token{text: s[start:end], normalized: normalize(s[start:end]), start: start, end: end}
+}
+
+// normalize menyamakan variasi penulisan, contoh "Muhammad" dan "Muhamad" menjadi "muhamad"
+func normalize(word string) string {
+	word = diacritics.Replace(strings.ToLower(word))
+	word = spellingRules.Replace(word)
+
+	// huruf dobel dianggap satu
+	var sb strings.Builder
+	var last rune
+	for _, r := range word {
+		if r == last && unicode.IsLetter(r) {
+			continue
+		}
+		sb.WriteRune(r)
+		last = r
+	}
+	return sb.String()
+}
+
+// QueryTokens kata-kata pencarian yang sudah dinormalisasi
+func QueryTokens(query string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	for _, t := range tokenize(query) {
+		if t.normalized != "" && !seen[t.normalized] {
+			seen[t.normalized] = true
+			terms = append(terms, t.normalized)
+		}
+	}
+	return terms
+}
+
+// similarity nilai 0..1, awalan kata dianggap hampir sama supaya pencarian sambil mengetik tetap cocok
+func similarity(query, word string) float64 {
+	if query == word {
+		return 1
+	}
+	if len(query) >= 3 && strings.HasPrefix(word, query) {
+		return 0.9
+	}
+
+	maxLen := len([]rune(query))
+	if n := len([]rune(word)); n > maxLen {
+		maxLen = n
+	}
+	if maxLen == 0 {
+		return 0
+	}
+	return 1 - float64(editDistance(query, word))/float64(maxLen)
+}
+
+// editDistance jarak Damerau-Levenshtein (optimal string alignment)
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = minInt(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// score menghitung relevansi dokumen terhadap kata pencarian dan membuat highlight,
+// hasil 0 berarti tidak ada kata yang cocok
+func score(terms []string, doc Document) (float64, map[string]string) {
+	var total, maxTotal float64
+	highlights := map[string]string{}
+
+	for _, field := range fieldWeights {
+		value := field.value(doc)
+		tokens := tokenize(value)
+		matched := map[int]bool{}
+
+		var fieldScore float64
+		for _, term := range terms {
+			best, bestIdx := 0.0, -1
+			for i, t := range tokens {
+				if s := similarity(term, t.normalized); s > best {
+					best, bestIdx = s, i
+				}
+			}
+			if best >= minSimilarity {
+				fieldScore += best
+				matched[bestIdx] = true
+			}
+		}
+
+		total += fieldScore * field.weight
+		maxTotal += float64(len(terms)) * field.weight
+		if len(matched) > 0 {
+			highlights[field.name] = highlight(value, tokens, matched)
+		}
+	}
+
+	if total == 0 || maxTotal == 0 {
+		return 0, nil
+	}
+	return total / maxTotal, highlights
+}
+
+// highlight membungkus kata yang cocok dengan <em>, sisa teks di escape supaya aman ditampilkan sebagai html
+func highlight(value string, tokens []token, matched map[int]bool) string {
+	var sb strings.Builder
+	last := 0
+	for i, t := range tokens {
+		if !matched[i] {
+			continue
+		}
+		sb.WriteString(html.EscapeString(value[last:t.start]))
+		sb.WriteString("<em>")
+		sb.WriteString(html.EscapeString(t.text))
+		sb.WriteString("</em>")
+		last = t.end
+	}
+	sb.WriteString(html.EscapeString(value[last:]))
+	return sb.String()
+}
+
+// rank menilai semua dokumen kandidat lalu mengurutkan dari yang paling relevan
+func rank(terms []string, docs []Document, limit int) []Hit {
+	hits := make([]Hit, 0, len(docs))
+	for _, doc := range docs {
+		s, highlights := score(terms, doc)
+		if s > 0 {
+			hits = append(hits, Hit{ID: doc.ID, Score: s, Highlights: highlights})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score == hits[j].Score {
+			return hits[i].ID < hits[j].ID
+		}
+		return hits[i].Score > hits[j].Score
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// TextSimilarity kemiripan dua teks 0..1, setiap kata di a dicari pasangan paling mirip di b
+// lalu dirata-rata dari dua arah supaya urutan kata tidak berpengaruh
+func TextSimilarity(a, b string) float64 {
+	ta, tb := QueryTokens(a), QueryTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	return (bestMatchAverage(ta, tb) + bestMatchAverage(tb, ta)) / 2
+}
+
+func bestMatchAverage(from, to []string) float64 {
+	var total float64
+	for _, f := range from {
+		best := 0.0
+		for _, t := range to {
+			if s := similarity(f, t); s > best {
+				best = s
+			}
+		}
+		total += best
+	}
+	return total / float64(len(from))
+}
@@ -0,0 +1,38 @@
+package search
+
+import "ginDatabaseMhs/model/entity"
+
+// Document data mahasiswa yang disimpan di index pencarian
+type Document struct {
+	ID          int64
+	OwnerID     int64
+	Name        string
+	Email       string
+	Address     string
+	PhoneNumber string
+}
+
+// Hit satu hasil pencarian, Highlights berisi nilai field dengan kata yang cocok dibungkus <em>
+type Hit struct {
+	ID         int64             `json:"id"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// Index subsystem pencarian full-text dengan fuzzy matching
+type Index interface {
+	Index(doc Document) error
+	Remove(id int64) error
+	Search(ownerID int64, query string, limit int) ([]Hit, error)
+}
+
+func DocumentFrom(data entity.User_data) Document {
+	return Document{
+		ID:          data.ID,
+		OwnerID:     data.UserID,
+		Name:        data.Name,
+		Email:       data.Email,
+		Address:     data.Address,
+		PhoneNumber: data.PhoneNumber,
+	}
+}
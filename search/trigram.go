@@ -0,0 +1,103 @@
+package search
+
+import "sort"
+
+// trigramIndex dipakai MemoryIndex untuk mencari kandidat pencarian fuzzy: dokumen yang punya trigram yang sama dengan kata di query.
+// Tidak aman dipakai bersamaan, pemanggil yang mengunci
+type trigramIndex struct {
+	grams map[string]map[int64]struct{}
+	docs  map[int64]trigramDoc
+}
+
+// trigramDoc pemilik dan trigram satu dokumen, disimpan supaya dokumen bisa dihapus dari index
+type trigramDoc struct {
+	ownerID int64
+	grams   []string
+}
+
+func newTrigramIndex() *trigramIndex {
+	return &trigramIndex{
+		grams: map[string]map[int64]struct{}{},
+		docs:  map[int64]trigramDoc{},
+	}
+}
+
+func (t *trigramIndex) add(doc Document) {
+	t.remove(doc.ID)
+	grams := documentTrigrams(doc)
+	t.docs[doc.ID] = trigramDoc{ownerID: doc.OwnerID, grams: grams}
+	for _, gram := range grams {
+		ids, ok := t.grams[gram]
+		if !ok {
+			ids = map[int64]struct{}{}
+			t.grams[gram] = ids
+		}
+		ids[doc.ID] = struct{}{}
+	}
+}
+
+func (t *trigramIndex) remove(id int64) {
+	doc, ok := t.docs[id]
+	if !ok {
+		return
+	}
+	for _, gram := range doc.grams {
+		if ids, ok := t.grams[gram]; ok {
+			delete(ids, id)
+			if len(ids) == 0 {
+				delete(t.grams, gram)
+			}
+		}
+	}
+	delete(t.docs, id)
+}
+
+// candidates id dokumen milik ownerID yang punya minimal satu trigram yang sama dengan terms, diurutkan
+// dari yang paling banyak trigram samanya. limit 0 berarti semua kandidat
+func (t *trigramIndex) candidates(ownerID int64, terms []string, limit int) []int64 {
+	shared := map[int64]int{}
+	for _, term := range terms {
+		for _, gram := range trigrams(term) {
+			for id := range t.grams[gram] {
+				if t.docs[id].ownerID == ownerID {
+					shared[id]++
+				}
+			}
+		}
+	}
+
+	ids := make([]int64, 0, len(shared))
+	for id := range shared {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if shared[ids[i]] == shared[ids[j]] {
+			return ids[i] < ids[j]
+		}
+		return shared[ids[i]] > shared[ids[j]]
+	})
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids
+}
+
+func documentTrigrams(doc Document) []string {
+	var grams []string
+	for _, field := range fieldWeights {
+		for _, t := range tokenize(field.value(doc)) {
+			grams = append(grams, trigrams(t.normalized)...)
+		}
+	}
+	return grams
+}
+
+// trigrams memecah kata menjadi potongan tiga huruf, diberi padding supaya kata pendek tetap punya trigram
+func trigrams(word string) []string {
+	runes := []rune("  " + word + " ")
+	grams := make([]string, 0, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+3]))
+	}
+	return grams
+}
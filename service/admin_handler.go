@@ -0,0 +1,311 @@
+package service
+
+import (
+	"errors"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/pagination"
+	"ginDatabaseMhs/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+// AdminRecordList daftar dan pencarian data mahasiswa dari semua pemilik. Parameter search, filter,
+// sort, fields dan include sama seperti /list-Search, ditambah owner_id (bisa lebih dari satu, pisahkan koma)
+func (h *Handler) AdminRecordList(ctx *gin.Context) {
+	adminID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var ownerIDs []int64
+	for _, raw := range splitList(ctx.Query("owner_id")) {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+				Message: "owner_id must be a list of numbers",
+				Status:  http.StatusBadRequest,
+			})
+			return
+		}
+		ownerIDs = append(ownerIDs, id)
+	}
+	// custom field berbeda per pemilik, jadi filter custom.* hanya tersedia untuk satu pemilik
+	schema := mahasiswaFilterSchema
+	if len(ownerIDs) == 1 {
+		fields, ok := h.customFields(ctx, ownerIDs[0])
+		if !ok {
+			return
+		}
+		schema = customFilterSchema(fields)
+	}
+	filter, err := parseMahasiswaFilter(ctx, schema)
+	if err != nil {
+		abortFilterError(ctx, err)
+		return
+	}
+	sort, err := parseSort(ctx.Query("sort"), sortableColumns)
+	if err != nil {
+		abortQueryError(ctx, err)
+		return
+	}
+	fs, err := parseFieldset(ctx)
+	if err != nil {
+		abortQueryError(ctx, err)
+		return
+	}
+	params, ok := keysetParams(ctx, mahasiswaKeys(sort))
+	if !ok {
+		return
+	}
+
+	dataMhs, err := h.MahasiswaRepository.GetAllRecords(adminID, ownerIDs, filter, params, fs.projection(sort))
+	if err != nil {
+		logrus.Errorf("failed when get all records: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	total, err := h.MahasiswaRepository.CountAllRecords(adminID, ownerIDs, filter)
+	if err != nil {
+		logrus.Errorf("failed when counting all records: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	dataMhs, page := pagination.Paginate(params, dataMhs, mahasiswaCursorValues(sort))
+	setLinkHeader(ctx, page)
+
+	owners, ok := h.ownersOf(ctx, dataMhs)
+	if !ok {
+		return
+	}
+	records := make([]request.AdminRecord, 0, len(dataMhs))
+	for _, data := range dataMhs {
+		records = append(records, request.AdminRecord{Record: fs.render(data), Owner: owners[data.UserID]})
+	}
+
+	ctx.JSON(http.StatusOK, request.SearchResponse{
+		Status: http.StatusOK,
+		Data:   records,
+		Total:  total,
+		Paging: &page,
+	})
+}
+
+func (h *Handler) AdminRecordGet(ctx *gin.Context) {
+	if _, ok := userIDFromContext(ctx); !ok {
+		return
+	}
+	data, ok := h.anyRecord(ctx)
+	if !ok {
+		return
+	}
+
+	owners, ok := h.ownersOf(ctx, []entity.User_data{*data})
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Success Get Data",
+		Data:    request.AdminRecord{Record: data, Owner: owners[data.UserID]},
+	})
+}
+
+// AdminRecordReassign memindahkan data beserta lampirannya ke pemilik lain
+func (h *Handler) AdminRecordReassign(ctx *gin.Context) {
+	if _, ok := userIDFromContext(ctx); !ok {
+		return
+	}
+
+	var req request.ReassignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	before, ok := h.anyRecord(ctx)
+	if !ok {
+		return
+	}
+
+	owner, err := h.MahasiswaRepository.GetUserByUsernameOrEmail(req.Owner, req.Owner)
+	if err != nil {
+		logrus.Errorf("failed when get user: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if owner == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "User not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	if owner.ID == before.UserID {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Data already belongs to this user",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	after, err := h.MahasiswaRepository.ReassignOwner(before.ID, owner.ID, auditActor(ctx))
+	if errors.Is(err, repository.ErrInUse) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "Data still has enrollments or attendance records, remove them before reassigning",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when reassigning data: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if after == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Data Reassigned",
+		Data:    request.AdminRecord{Record: after, Owner: ownerOf(*owner)},
+	})
+}
+
+func (h *Handler) anyRecord(ctx *gin.Context) (*entity.User_data, bool) {
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return nil, false
+	}
+
+	data, err := h.MahasiswaRepository.GetRecordByID(mhsID)
+	if err != nil {
+		logrus.Errorf("failed when get data by id: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if data == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+	return data, true
+}
+
+// ownersOf data pemilik dari setiap data, dikelompokkan berdasarkan user id
+func (h *Handler) ownersOf(ctx *gin.Context, data []entity.User_data) (map[int64]*request.Owner, bool) {
+	var ids []int64
+	for _, d := range data {
+		ids = append(ids, d.UserID)
+	}
+	owners := map[int64]*request.Owner{}
+	if len(ids) == 0 {
+		return owners, true
+	}
+
+	users, err := h.MahasiswaRepository.GetUsersByIDs(uniqueIDs(ids))
+	if err != nil {
+		logrus.Errorf("failed when get owners: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	for _, user := range users {
+		owners[user.ID] = ownerOf(user)
+	}
+	return owners, true
+}
+
+func ownerOf(user entity.User) *request.Owner {
+	return &request.Owner{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+	}
+}
+
+// AdminUserRole mengganti role pengguna, satu-satunya jalan untuk memberi akses admin
+func (h *Handler) AdminUserRole(ctx *gin.Context) {
+	adminID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	userID, err := strconv.ParseInt(ctx.Param("user_id"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid user_id",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	var req request.RoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	// admin tidak boleh menurunkan role nya sendiri supaya selalu ada admin yang tersisa
+	if userID == adminID {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Cannot change your own role",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	user, err := h.MahasiswaRepository.UpdateUserRole(userID, req.Role)
+	if err != nil {
+		logrus.Errorf("failed when updating user role: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if user == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "User not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	user.Password = ""
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Role updated",
+		Data:    user,
+	})
+}
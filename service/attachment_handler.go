@@ -0,0 +1,564 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"ginDatabaseMhs/imaging"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/repository"
+	"ginDatabaseMhs/storage"
+	"ginDatabaseMhs/upload"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// multipartOverhead ruang tambahan untuk boundary dan field lain di luar file saat membatasi body request
+const multipartOverhead = 1 << 20
+
+// AttachmentUpload upload lampiran lewat form record_id dan file, disimpan di storage yang dikonfigurasi.
+// Tipe file ditentukan dari isinya dan ukurannya dibatasi sesuai UploadPolicy
+func (h *Handler) AttachmentUpload(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	// file dibaca lebih dulu karena form baru di parse di sini, termasuk record_id
+	file, inspected, src, ok := h.uploadedAttachment(ctx)
+	if !ok {
+		return
+	}
+	defer src.Close()
+
+	mhsID, err := strconv.ParseInt(ctx.PostForm("record_id"), 10, 64)
+	if err != nil || mhsID < 1 {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "record_id must be a positive integer",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	mhs, ok := h.findRecord(ctx, mhsID, userID)
+	if !ok {
+		return
+	}
+	if !h.canEdit(ctx, mhs, userID) {
+		return
+	}
+
+	stored, ok := h.attachmentFile(ctx, file.Filename, inspected, file.Size)
+	if !ok {
+		return
+	}
+	attachment, err := h.MahasiswaRepository.SaveAttachment(ctx.Request.Context(), mhsID, userID, stored, auditActor(ctx))
+	if abortUploadError(ctx, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when saving attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, request.SuccessMessage{
+		Status:  http.StatusCreated,
+		Message: "Attachment Uploaded",
+		Data:    attachment,
+	})
+}
+
+// AttachmentDownload stream file lampiran dari storage, mendukung ETag (If-None-Match) dan satu HTTP Range.
+// Lampiran hanya bisa diunduh user yang bisa melihat datanya
+func (h *Handler) AttachmentDownload(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	attachment, _, ok := h.findAttachment(ctx, userID)
+	if !ok {
+		return
+	}
+	key, fileName, ok := attachmentObject(ctx, attachment)
+	if !ok {
+		return
+	}
+
+	store, ok := h.storageDriver(ctx, attachment.Storage)
+	if !ok {
+		return
+	}
+	info, err := store.Stat(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Attachment file not found in storage",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when stat attachment file: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = info.ContentType
+	}
+	disposition := "inline"
+	if download, _ := strconv.ParseBool(ctx.Query("download")); download {
+		disposition = "attachment"
+	}
+
+	header := ctx.Writer.Header()
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("Cache-Control", "private")
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": fileName}))
+	if info.ETag != "" {
+		header.Set("ETag", info.ETag)
+	}
+	if !info.ModTime.IsZero() {
+		header.Set("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+	}
+
+	if info.ETag != "" && etagMatch(ctx.GetHeader("If-None-Match"), info.ETag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	// If-Range yang tidak cocok berarti file sudah berubah, kirim file utuh
+	rangeHeader := ctx.GetHeader("Range")
+	if ifRange := ctx.GetHeader("If-Range"); ifRange != "" && ifRange != info.ETag {
+		rangeHeader = ""
+	}
+	offset, length, partial, err := parseByteRange(rangeHeader, info.Size)
+	if err != nil {
+		header.Set("Content-Range", fmt.Sprintf("bytes */%d", info.Size))
+		ctx.AbortWithStatusJSON(http.StatusRequestedRangeNotSatisfiable, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusRequestedRangeNotSatisfiable,
+		})
+		return
+	}
+
+	var body io.ReadCloser
+	if partial {
+		body, _, err = store.GetRange(ctx, key, offset, length)
+	} else {
+		body, _, err = store.Get(ctx, key)
+	}
+	if err != nil {
+		logrus.Errorf("failed when reading attachment file: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	defer body.Close()
+
+	status := http.StatusOK
+	extraHeaders := map[string]string{}
+	if partial {
+		status = http.StatusPartialContent
+		extraHeaders["Content-Range"] = fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, info.Size)
+	}
+	ctx.DataFromReader(status, length, contentType, body, extraHeaders)
+}
+
+// AttachmentDelete menghapus satu lampiran beserta filenya, hanya pemilik atau editor data
+func (h *Handler) AttachmentDelete(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	attachment, mhs, ok := h.findAttachment(ctx, userID)
+	if !ok {
+		return
+	}
+	if !h.canEdit(ctx, mhs, userID) {
+		return
+	}
+
+	deleted, err := h.MahasiswaRepository.DeleteAttachment(ctx.Request.Context(), attachment.ID, auditActor(ctx))
+	if err != nil {
+		logrus.Errorf("failed when deleting attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if deleted == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.DeleteResponse{
+		Status:  http.StatusOK,
+		Message: "Attachment Deleted",
+	})
+}
+
+// AttachmentReplace mengganti file lampiran lewat form field file, id dan urutannya tetap
+func (h *Handler) AttachmentReplace(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	attachment, mhs, ok := h.findAttachment(ctx, userID)
+	if !ok {
+		return
+	}
+	if !h.canEdit(ctx, mhs, userID) {
+		return
+	}
+
+	file, inspected, src, ok := h.uploadedAttachment(ctx)
+	if !ok {
+		return
+	}
+	defer src.Close()
+
+	stored, ok := h.attachmentFile(ctx, file.Filename, inspected, file.Size)
+	if !ok {
+		return
+	}
+	replaced, err := h.MahasiswaRepository.ReplaceAttachmentFile(ctx.Request.Context(), attachment, stored, auditActor(ctx))
+	if abortUploadError(ctx, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "Attachment was changed or deleted by another request, please retry",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when replacing attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Attachment Replaced",
+		Data:    replaced,
+	})
+}
+
+// AttachmentReorder menyusun ulang semua lampiran data sekaligus, attachment_ids harus lengkap
+func (h *Handler) AttachmentReorder(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req request.AttachmentOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	mhs, ok := h.findRecord(ctx, mhsID, userID)
+	if !ok {
+		return
+	}
+	if !h.canEdit(ctx, mhs, userID) {
+		return
+	}
+
+	attachments, err := h.MahasiswaRepository.ReorderAttachments(mhsID, req.AttachmentIDs, auditActor(ctx))
+	if errors.Is(err, repository.ErrAttachmentOrder) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when reordering attachments: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Attachments Reordered",
+		Data:    attachments,
+	})
+}
+
+// findAttachment lampiran dari path parameter id beserta datanya,
+// 404 kalau lampiran tidak ada atau datanya tidak bisa dilihat user
+func (h *Handler) findAttachment(ctx *gin.Context, userID int64) (*entity.Attachment, *entity.User_data, bool) {
+	id, ok := paramID(ctx, "id")
+	if !ok {
+		return nil, nil, false
+	}
+	attachment, err := h.MahasiswaRepository.GetAttachmentByID(id)
+	if err != nil {
+		logrus.Errorf("failed when get attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, nil, false
+	}
+	if attachment == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, nil, false
+	}
+	mhs, ok := h.findRecord(ctx, attachment.UserID, userID)
+	if !ok {
+		return nil, nil, false
+	}
+	return attachment, mhs, true
+}
+
+// uploadedAttachment file dari form field "file" yang sudah diperiksa UploadPolicy.
+// Body request dibatasi sebelum form di parse supaya file raksasa tidak ditulis ke disk sementara.
+// File yang dikembalikan harus ditutup pemanggil
+func (h *Handler) uploadedAttachment(ctx *gin.Context) (*multipart.FileHeader, *upload.File, multipart.File, bool) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.UploadPolicy.MaxSize()+multipartOverhead)
+
+	file, err := ctx.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		abortUploadError(ctx, &upload.Error{
+			Code:    upload.CodeTooLarge,
+			Message: fmt.Sprintf("files must not exceed %d bytes", h.UploadPolicy.MaxSize()),
+		})
+		return nil, nil, nil, false
+	}
+	if err != nil {
+		abortUploadError(ctx, &upload.Error{Code: upload.CodeMissing, Message: "No File Upload"})
+		return nil, nil, nil, false
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		logrus.Errorf("failed when opening uploaded file: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, nil, nil, false
+	}
+	inspected, err := h.UploadPolicy.Inspect(src, file.Filename, file.Size)
+	if err != nil {
+		src.Close()
+		if !abortUploadError(ctx, err) {
+			logrus.Errorf("failed when reading uploaded file: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+				Message: "Internal Server Error",
+				Status:  http.StatusInternalServerError,
+			})
+		}
+		return nil, nil, nil, false
+	}
+	return file, inspected, src, true
+}
+
+// attachmentFile menyiapkan file yang sudah diperiksa untuk disimpan. Gambar dilewatkan pipeline
+// (orientasi, buang EXIF, encode ulang, variant), file lain disimpan apa adanya
+func (h *Handler) attachmentFile(ctx *gin.Context, fileName string, inspected *upload.File, size int64) (repository.AttachmentFile, bool) {
+	file := repository.AttachmentFile{
+		Name:        fileName,
+		ContentType: inspected.ContentType,
+		Size:        size,
+		Reader:      inspected.Reader,
+	}
+	if !imaging.Supported(inspected.ContentType) {
+		return file, true
+	}
+
+	processed, err := h.ImageProcessor.Process(inspected.Reader, inspected.ContentType)
+	if errors.Is(err, imaging.ErrTooManyPixels) {
+		abortUploadError(ctx, &upload.Error{Code: upload.CodeImageTooLarge, Message: err.Error()})
+		return file, false
+	}
+	if err != nil {
+		if !abortUploadError(ctx, err) {
+			abortUploadError(ctx, &upload.Error{
+				Code:    upload.CodeInvalidImage,
+				Message: fmt.Sprintf("image could not be processed: %v", err),
+			})
+		}
+		return file, false
+	}
+
+	// WebP disimpan sebagai JPEG, nama file ikut diganti supaya sesuai isinya
+	if processed.Original.ContentType != inspected.ContentType {
+		file.Name = strings.TrimSuffix(fileName, path.Ext(fileName)) + processed.Original.Ext
+	}
+	file.ContentType = processed.Original.ContentType
+	file.Size = int64(len(processed.Original.Data))
+	file.Reader = bytes.NewReader(processed.Original.Data)
+	file.Variants = make(map[string][]byte, len(processed.Variants))
+	for name, variant := range processed.Variants {
+		file.Variants[name] = variant.Data
+	}
+	return file, true
+}
+
+// attachmentObject key dan nama file yang diunduh, parameter variant memilih thumbnail atau medium
+func attachmentObject(ctx *gin.Context, attachment *entity.Attachment) (string, string, bool) {
+	fileName := attachment.FileName
+	if fileName == "" {
+		fileName = path.Base(attachment.Path)
+	}
+	variant := ctx.Query("variant")
+	if variant == "" {
+		return attachment.Path, fileName, true
+	}
+	if !attachment.HasVariant(variant) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: fmt.Sprintf("Attachment has no %q variant", variant),
+			Status:  http.StatusNotFound,
+		})
+		return "", "", false
+	}
+	ext := path.Ext(fileName)
+	return attachment.VariantKey(variant), strings.TrimSuffix(fileName, ext) + "_" + variant + ext, true
+}
+
+// abortUploadError mengirim response untuk *upload.Error (juga yang muncul saat file di stream ke storage),
+// false kalau err bukan penolakan upload
+func abortUploadError(ctx *gin.Context, err error) bool {
+	var uploadErr *upload.Error
+	if !errors.As(err, &uploadErr) {
+		return false
+	}
+
+	status := http.StatusUnprocessableEntity
+	switch uploadErr.Code {
+	case upload.CodeMissing:
+		status = http.StatusBadRequest
+	case upload.CodeTooLarge:
+		status = http.StatusRequestEntityTooLarge
+	case upload.CodeUnsupportedType, upload.CodeExtensionMismatch:
+		status = http.StatusUnsupportedMediaType
+	}
+	ctx.AbortWithStatusJSON(status, respErr.UploadError{
+		Message: uploadErr.Message,
+		Status:  status,
+		Code:    uploadErr.Code,
+	})
+	return true
+}
+
+// parseByteRange membaca header "bytes=start-end", "bytes=start-" atau "bytes=-suffix".
+// Header kosong, multi range atau range yang tidak valid (contoh bytes=5-3) diabaikan dan dilayani sebagai
+// file utuh (partial false) sesuai RFC 9110. Error hanya untuk range valid yang tidak bisa dipenuhi (416)
+func parseByteRange(header string, size int64) (offset, length int64, partial bool, err error) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, size, false, nil
+	}
+	startStr, endStr, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, size, false, nil
+	}
+
+	if startStr == "" {
+		suffix, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || suffix < 0 {
+			return 0, size, false, nil
+		}
+		if suffix == 0 || size == 0 {
+			return 0, 0, false, errors.New("range not satisfiable")
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return size - suffix, suffix, true, nil
+	}
+
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil || start < 0 {
+		return 0, size, false, nil
+	}
+	end := size - 1
+	if endStr != "" {
+		end, err = strconv.ParseInt(endStr, 10, 64)
+		if err != nil || end < start {
+			return 0, size, false, nil
+		}
+	}
+	if start >= size {
+		return 0, 0, false, errors.New("range not satisfiable")
+	}
+	if end >= size {
+		end = size - 1
+	}
+	return start, end - start + 1, true, nil
+}
+
+// etagMatch mencocokkan header If-None-Match yang bisa berisi beberapa ETag atau *
+func etagMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// storageDriver storage tempat file lampiran disimpan, driver yang tidak dikonfigurasi dianggap error server
+func (h *Handler) storageDriver(ctx *gin.Context, driver string) (storage.Storage, bool) {
+	store, err := h.Storage.For(driver)
+	if err != nil {
+		logrus.Errorf("failed when get attachment storage: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	return store, true
+}
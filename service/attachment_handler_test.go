@@ -0,0 +1,41 @@
+package service
+
+import "testing"
+
+func TestParseByteRange(t *testing.T) {
+	const size = 10
+	tests := []struct {
+		header         string
+		offset, length int64
+		partial        bool
+		unsatisfiable  bool
+	}{
+		{"", 0, size, false, false},
+		{"bytes=0-4", 0, 5, true, false},
+		{"bytes=5-", 5, 5, true, false},
+		{"bytes=-3", 7, 3, true, false},
+		{"bytes=-30", 0, size, true, false},
+		{"bytes=8-100", 8, 2, true, false},
+		// tidak valid, diabaikan dan dilayani sebagai file utuh
+		{"bytes=5-3", 0, size, false, false},
+		{"bytes=abc", 0, size, false, false},
+		{"bytes=a-3", 0, size, false, false},
+		{"bytes=1-b", 0, size, false, false},
+		{"items=0-4", 0, size, false, false},
+		{"bytes=0-1,4-5", 0, size, false, false},
+		// valid tapi tidak bisa dipenuhi
+		{"bytes=10-", 0, 0, false, true},
+		{"bytes=12-15", 0, 0, false, true},
+		{"bytes=-0", 0, 0, false, true},
+	}
+	for _, tt := range tests {
+		offset, length, partial, err := parseByteRange(tt.header, size)
+		if (err != nil) != tt.unsatisfiable {
+			t.Errorf("%q: error %v, unsatisfiable %v", tt.header, err, tt.unsatisfiable)
+			continue
+		}
+		if offset != tt.offset || length != tt.length || partial != tt.partial {
+			t.Errorf("%q: got %d,%d,%v want %d,%d,%v", tt.header, offset, length, partial, tt.offset, tt.length, tt.partial)
+		}
+	}
+}
@@ -0,0 +1,457 @@
+package service
+
+import (
+	"fmt"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func (h *Handler) ClassSessionList(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	courseID, err := queryInt64(ctx, "course_id")
+	if err != nil {
+		abortQueryError(ctx, err)
+		return
+	}
+	semesterID, err := queryInt64(ctx, "semester_id")
+	if err != nil {
+		abortQueryError(ctx, err)
+		return
+	}
+	params, ok := keysetParams(ctx, []pagination.Key{{Expr: "date"}})
+	if !ok {
+		return
+	}
+
+	sessions, err := h.MahasiswaRepository.GetClassSessions(userID, courseID, semesterID, params)
+	if err != nil {
+		logrus.Errorf("failed when get class sessions: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	sessions, page := pagination.Paginate(params, sessions, func(session entity.ClassSession) []interface{} {
+		return []interface{}{session.Date.String(), session.ID}
+	})
+	setLinkHeader(ctx, page)
+
+	ctx.JSON(http.StatusOK, request.ListResponse{
+		Status:  http.StatusOK,
+		Message: "Success Get Class Sessions",
+		Data:    sessions,
+		Paging:  &page,
+	})
+}
+
+func (h *Handler) ClassSessionCreate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	session, ok := h.bindClassSession(ctx, userID, 0)
+	if !ok {
+		return
+	}
+	if err := h.MahasiswaRepository.CreateClassSession(session); err != nil {
+		logrus.Errorf("failed when creating class session: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, request.SuccessMessage{
+		Status:  http.StatusCreated,
+		Message: "Class Session Created",
+		Data:    session,
+	})
+}
+
+func (h *Handler) ClassSessionGet(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	session, ok := h.findClassSession(ctx, userID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Success Get Class Session",
+		Data:    session,
+	})
+}
+
+func (h *Handler) ClassSessionUpdate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	current, ok := h.findClassSession(ctx, userID)
+	if !ok {
+		return
+	}
+
+	session, ok := h.bindClassSession(ctx, userID, current.ID)
+	if !ok {
+		return
+	}
+	_, err := h.MahasiswaRepository.UpdateClassSession(current.ID, userID, map[string]interface{}{
+		"course_id":      session.CourseID,
+		"semester_id":    session.SemesterID,
+		"meeting_number": session.MeetingNumber,
+		"date":           session.Date,
+		"topic":          session.Topic,
+	})
+	if err != nil {
+		logrus.Errorf("failed when updating class session: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	session.ID = current.ID
+	session.CreatedAt = current.CreatedAt
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Class Session Updated",
+		Data:    session,
+	})
+}
+
+func (h *Handler) ClassSessionDelete(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	session, ok := h.findClassSession(ctx, userID)
+	if !ok {
+		return
+	}
+
+	if _, err := h.MahasiswaRepository.DeleteClassSession(session.ID, userID); err != nil {
+		logrus.Errorf("failed when deleting class session: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Class Session Deleted",
+	})
+}
+
+func (h *Handler) AttendanceList(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	session, ok := h.findClassSession(ctx, userID)
+	if !ok {
+		return
+	}
+
+	attendances, err := h.MahasiswaRepository.GetAttendanceBySession(session.ID)
+	if err != nil {
+		logrus.Errorf("failed when get attendance: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Success Get Attendance",
+		Data:    attendances,
+	})
+}
+
+// AttendanceSubmit menyimpan kehadiran satu pertemuan sekaligus, semua mahasiswa harus
+// terdaftar di mata kuliah dan semester pertemuan tersebut. Kalau ada yang tidak valid tidak ada yang disimpan
+func (h *Handler) AttendanceSubmit(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.AttendanceBulkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	session, ok := h.findClassSession(ctx, userID)
+	if !ok {
+		return
+	}
+
+	enrolledIDs, err := h.MahasiswaRepository.GetEnrolledRecordIDs(session.UserID, session.CourseID, session.SemesterID)
+	if err != nil {
+		logrus.Errorf("failed when get enrolled students: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	enrolled := map[int64]bool{}
+	for _, id := range enrolledIDs {
+		enrolled[id] = true
+	}
+
+	var errs []string
+	seen := map[int64]bool{}
+	attendances := make([]entity.Attendance, 0, len(req.Records))
+	for i, record := range req.Records {
+		switch {
+		case seen[record.RecordID]:
+			errs = append(errs, fmt.Sprintf("records[%d]: record %d is listed more than once", i, record.RecordID))
+		case !enrolled[record.RecordID]:
+			errs = append(errs, fmt.Sprintf("records[%d]: record %d is not enrolled in this course", i, record.RecordID))
+		}
+		seen[record.RecordID] = true
+		attendances = append(attendances, entity.Attendance{
+			SessionID: session.ID,
+			RecordID:  record.RecordID,
+			Status:    record.Status,
+			Note:      strings.TrimSpace(record.Note),
+		})
+	}
+	if len(errs) > 0 {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid attendance records: " + strings.Join(errs, "; "),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.MahasiswaRepository.SaveAttendances(attendances); err != nil {
+		logrus.Errorf("failed when saving attendance: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: fmt.Sprintf("Attendance saved for %d students", len(attendances)),
+		Data:    attendances,
+	})
+}
+
+// AttendanceCourseReport rekap kehadiran semua mahasiswa di satu mata kuliah dan semester
+func (h *Handler) AttendanceCourseReport(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	courseID, err := queryInt64(ctx, "course_id")
+	if err == nil && courseID == 0 {
+		err = fmt.Errorf("course_id is required")
+	}
+	if err != nil {
+		abortQueryError(ctx, err)
+		return
+	}
+	semesterID, err := queryInt64(ctx, "semester_id")
+	if err != nil {
+		abortQueryError(ctx, err)
+		return
+	}
+	threshold, ok := h.attendanceThreshold(ctx)
+	if !ok {
+		return
+	}
+	if _, ok := h.findCourse(ctx, courseID, userID); !ok {
+		return
+	}
+
+	stats, err := h.MahasiswaRepository.GetAttendanceStats(courseID, semesterID, 0)
+	if err != nil {
+		logrus.Errorf("failed when get attendance report: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attendanceReport(stats, threshold))
+}
+
+// AttendanceStudentReport rekap kehadiran satu mahasiswa di semua mata kuliah yang diambil
+func (h *Handler) AttendanceStudentReport(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+	threshold, ok := h.attendanceThreshold(ctx)
+	if !ok {
+		return
+	}
+	if _, ok := h.findRecord(ctx, mhsID, userID); !ok {
+		return
+	}
+
+	stats, err := h.MahasiswaRepository.GetAttendanceStats(0, 0, mhsID)
+	if err != nil {
+		logrus.Errorf("failed when get attendance report: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attendanceReport(stats, threshold))
+}
+
+// attendanceReport menghitung persentase kehadiran, mata kuliah tanpa pertemuan tidak ditandai
+func attendanceReport(stats []entity.AttendanceStat, threshold float64) request.AttendanceReportResponse {
+	response := request.AttendanceReportResponse{
+		Status:    http.StatusOK,
+		Threshold: threshold,
+		Data:      make([]request.AttendanceReport, 0, len(stats)),
+	}
+	for _, stat := range stats {
+		report := request.AttendanceReport{AttendanceStat: stat}
+		if stat.Sessions > 0 {
+			report.Percentage = math.Round(float64(stat.Present)/float64(stat.Sessions)*10000) / 100
+			report.BelowThreshold = report.Percentage < threshold
+		}
+		if report.BelowThreshold {
+			response.Flagged++
+		}
+		response.Data = append(response.Data, report)
+	}
+	return response
+}
+
+// attendanceThreshold dari query threshold, kalau kosong memakai konfigurasi
+func (h *Handler) attendanceThreshold(ctx *gin.Context) (float64, bool) {
+	raw := ctx.Query("threshold")
+	if raw == "" {
+		return h.AttendanceThreshold, true
+	}
+	threshold, err := strconv.ParseFloat(raw, 64)
+	if err != nil || threshold < 0 || threshold > 100 {
+		abortQueryError(ctx, fmt.Errorf("threshold must be a number between 0 and 100"))
+		return 0, false
+	}
+	return threshold, true
+}
+
+// bindClassSession membaca body pertemuan, mata kuliah dan semester harus milik user
+// dan nomor pertemuan tidak boleh dipakai pertemuan lain
+func (h *Handler) bindClassSession(ctx *gin.Context, userID, exceptID int64) (*entity.ClassSession, bool) {
+	var req request.ClassSessionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+	date, err := entity.ParseDate(req.Date)
+	if err == nil && date.IsZero() {
+		err = fmt.Errorf("date is required")
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	if _, ok := h.findCourse(ctx, req.CourseID, userID); !ok {
+		return nil, false
+	}
+	if _, ok := h.findSemester(ctx, req.SemesterID, userID); !ok {
+		return nil, false
+	}
+
+	existing, err := h.MahasiswaRepository.GetClassSessionByMeeting(req.CourseID, req.SemesterID, req.MeetingNumber)
+	if err != nil {
+		logrus.Errorf("failed when checking meeting number: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if existing != nil && existing.ID != exceptID {
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: fmt.Sprintf("Meeting %d already exists for this course and semester", req.MeetingNumber),
+			Status:  http.StatusConflict,
+		})
+		return nil, false
+	}
+
+	return &entity.ClassSession{
+		UserID:        userID,
+		CourseID:      req.CourseID,
+		SemesterID:    req.SemesterID,
+		MeetingNumber: req.MeetingNumber,
+		Date:          date,
+		Topic:         strings.TrimSpace(req.Topic),
+	}, true
+}
+
+func (h *Handler) findClassSession(ctx *gin.Context, userID int64) (*entity.ClassSession, bool) {
+	sessionID, ok := paramID(ctx, "id")
+	if !ok {
+		return nil, false
+	}
+
+	session, err := h.MahasiswaRepository.GetClassSessionByID(sessionID, userID)
+	if err != nil {
+		logrus.Errorf("failed when get class session: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if session == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Class session not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+	return session, true
+}
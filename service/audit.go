@@ -0,0 +1,13 @@
+package service
+
+import (
+	"ginDatabaseMhs/audit"
+	"github.com/gin-gonic/gin"
+)
+
+// auditActor pelaku perubahan dari token dan request id, dicatat repository bersama perubahannya
+func auditActor(ctx *gin.Context) audit.Actor {
+	actorID, _ := ctx.Get("user_id")
+	actorIDInt64, _ := actorID.(int64)
+	return audit.Actor{ID: actorIDInt64, RequestID: ctx.GetString("request_id")}
+}
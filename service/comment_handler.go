@@ -0,0 +1,297 @@
+package service
+
+import (
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+// CommentList comment terbaru dulu, comment private milik user lain tidak ikut
+func (h *Handler) CommentList(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+	if _, ok := h.findRecord(ctx, mhsID, userID); !ok {
+		return
+	}
+	params, ok := keysetParams(ctx, []pagination.Key{{Expr: "id", Desc: true}})
+	if !ok {
+		return
+	}
+
+	comments, err := h.MahasiswaRepository.GetCommentsByRecord(mhsID, userID, params)
+	if err != nil {
+		logrus.Errorf("failed when get comments: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	comments, page := pagination.Paginate(params, comments, func(comment entity.Comment) []interface{} {
+		return []interface{}{comment.ID, comment.ID}
+	})
+	setLinkHeader(ctx, page)
+
+	ctx.JSON(http.StatusOK, request.ListResponse{
+		Status:  http.StatusOK,
+		Message: "Success Get Comments",
+		Data:    comments,
+		Paging:  &page,
+	})
+}
+
+// CommentCreate semua user yang bisa melihat data boleh menulis comment
+func (h *Handler) CommentCreate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req request.CommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	body, ok := commentBody(ctx, req.Body)
+	if !ok {
+		return
+	}
+	if _, ok := h.findRecord(ctx, mhsID, userID); !ok {
+		return
+	}
+
+	comment := &entity.Comment{
+		RecordID:   mhsID,
+		AuthorID:   userID,
+		Body:       body,
+		Visibility: req.Visibility,
+	}
+	if comment.Visibility == "" {
+		comment.Visibility = entity.CommentVisibilityShared
+	}
+	if err := h.MahasiswaRepository.CreateComment(comment); err != nil {
+		logrus.Errorf("failed when creating comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	comment.Author = ctx.GetString("username")
+
+	ctx.JSON(http.StatusCreated, request.SuccessMessage{
+		Status:  http.StatusCreated,
+		Message: "Comment Created",
+		Data:    comment,
+	})
+}
+
+func (h *Handler) CommentGet(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	comment, _, ok := h.findComment(ctx, userID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Success Get Comment",
+		Data:    comment,
+	})
+}
+
+// CommentUpdate hanya penulis yang boleh mengubah comment, isi sebelumnya disimpan di history
+func (h *Handler) CommentUpdate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.CommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	body, ok := commentBody(ctx, req.Body)
+	if !ok {
+		return
+	}
+	comment, _, ok := h.findComment(ctx, userID)
+	if !ok {
+		return
+	}
+	if comment.AuthorID != userID {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, respErr.ErrorResponse{
+			Message: "Only the author can edit this comment",
+			Status:  http.StatusForbidden,
+		})
+		return
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = comment.Visibility
+	}
+	if body == comment.Body && visibility == comment.Visibility {
+		ctx.JSON(http.StatusOK, request.SuccessMessage{
+			Status:  http.StatusOK,
+			Message: "Not Change",
+			Data:    comment,
+		})
+		return
+	}
+
+	if err := h.MahasiswaRepository.UpdateComment(comment, body, visibility, userID); err != nil {
+		logrus.Errorf("failed when updating comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	updated, err := h.MahasiswaRepository.GetCommentByID(comment.ID)
+	if err != nil || updated == nil {
+		logrus.Errorf("failed when get updated comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Comment Updated",
+		Data:    updated,
+	})
+}
+
+// CommentDelete penulis atau pemilik data boleh menghapus comment, history-nya ikut terhapus
+func (h *Handler) CommentDelete(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	comment, mhs, ok := h.findComment(ctx, userID)
+	if !ok {
+		return
+	}
+	if comment.AuthorID != userID && mhs.UserID != userID {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, respErr.ErrorResponse{
+			Message: "Only the author or the record owner can delete this comment",
+			Status:  http.StatusForbidden,
+		})
+		return
+	}
+
+	if _, err := h.MahasiswaRepository.DeleteComment(comment.ID); err != nil {
+		logrus.Errorf("failed when deleting comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Comment Deleted",
+	})
+}
+
+// CommentHistory isi comment sebelum setiap perubahan, dari yang paling lama
+func (h *Handler) CommentHistory(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	comment, _, ok := h.findComment(ctx, userID)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.MahasiswaRepository.GetCommentRevisions(comment.ID)
+	if err != nil {
+		logrus.Errorf("failed when get comment history: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Success Get Comment History",
+		Data:    revisions,
+	})
+}
+
+// findComment comment yang terlihat oleh user: datanya bisa diakses user dan comment-nya
+// shared atau ditulis oleh user sendiri. Data di trash tidak bisa diakses jadi comment-nya juga tidak
+func (h *Handler) findComment(ctx *gin.Context, userID int64) (*entity.Comment, *entity.User_data, bool) {
+	commentID, ok := paramID(ctx, "id")
+	if !ok {
+		return nil, nil, false
+	}
+
+	comment, err := h.MahasiswaRepository.GetCommentByID(commentID)
+	if err != nil {
+		logrus.Errorf("failed when get comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, nil, false
+	}
+	if comment == nil || (comment.Visibility == entity.CommentVisibilityPrivate && comment.AuthorID != userID) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Comment not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, nil, false
+	}
+
+	mhs, ok := h.findRecord(ctx, comment.RecordID, userID)
+	if !ok {
+		return nil, nil, false
+	}
+	return comment, mhs, true
+}
+
+func commentBody(ctx *gin.Context, raw string) (string, bool) {
+	body := strings.TrimSpace(raw)
+	if body == "" {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "body must not be empty",
+			Status:  http.StatusBadRequest,
+		})
+		return "", false
+	}
+	return body, true
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"errors"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/pagination"
+	"ginDatabaseMhs/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+func (h *Handler) CourseList(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	params, ok := keysetParams(ctx, nil)
+	if !ok {
+		return
+	}
+
+	courses, err := h.MahasiswaRepository.GetCoursesByUser(userID, params)
+	if err != nil {
+		logrus.Errorf("failed when get courses: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	courses, page := pagination.Paginate(params, courses, func(course entity.Course) []interface{} {
+		return []interface{}{course.ID}
+	})
+	setLinkHeader(ctx, page)
+
+	ctx.JSON(http.StatusOK, request.ListResponse{
+		Status:  http.StatusOK,
+		Message: "Success Get Courses",
+		Data:    courses,
+		Paging:  &page,
+	})
+}
+
+func (h *Handler) CourseCreate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.CourseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	if !h.courseCodeAvailable(ctx, userID, req.Code, 0) {
+		return
+	}
+
+	course := &entity.Course{
+		UserID:  userID,
+		Code:    req.Code,
+		Name:    strings.TrimSpace(req.Name),
+		Credits: req.Credits,
+	}
+	if err := h.MahasiswaRepository.CreateCourse(course); err != nil {
+		logrus.Errorf("failed when creating course: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, request.SuccessMessage{
+		Status:  http.StatusCreated,
+		Message: "Course Created",
+		Data:    course,
+	})
+}
+
+func (h *Handler) CourseGet(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	courseID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	course, ok := h.findCourse(ctx, courseID, userID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Success Get Course",
+		Data:    course,
+	})
+}
+
+func (h *Handler) CourseUpdate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	courseID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req request.CourseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+
+	if _, ok := h.findCourse(ctx, courseID, userID); !ok {
+		return
+	}
+	if !h.courseCodeAvailable(ctx, userID, req.Code, courseID) {
+		return
+	}
+
+	_, err := h.MahasiswaRepository.UpdateCourse(courseID, userID, map[string]interface{}{
+		"code":    req.Code,
+		"name":    strings.TrimSpace(req.Name),
+		"credits": req.Credits,
+	})
+	if err != nil {
+		logrus.Errorf("failed when updating course: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	course, ok := h.findCourse(ctx, courseID, userID)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Course Updated",
+		Data:    course,
+	})
+}
+
+func (h *Handler) CourseDelete(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	courseID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	rowsAffected, err := h.MahasiswaRepository.DeleteCourse(courseID, userID)
+	if errors.Is(err, repository.ErrInUse) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "Course still has enrollments",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when deleting course: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if rowsAffected == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Course not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Course Deleted",
+	})
+}
+
+func (h *Handler) findCourse(ctx *gin.Context, courseID, userID int64) (*entity.Course, bool) {
+	course, err := h.MahasiswaRepository.GetCourseByID(courseID, userID)
+	if err != nil {
+		logrus.Errorf("failed when get course: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if course == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Course not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+	return course, true
+}
+
+// courseCodeAvailable kode mata kuliah unik per user, exceptID untuk mata kuliah yang sedang diubah
+func (h *Handler) courseCodeAvailable(ctx *gin.Context, userID int64, code string, exceptID int64) bool {
+	existing, err := h.MahasiswaRepository.GetCourseByCode(userID, code)
+	if err != nil {
+		logrus.Errorf("failed when checking course code: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return false
+	}
+	if existing != nil && existing.ID != exceptID {
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "Course with the same code already exists",
+			Status:  http.StatusConflict,
+		})
+		return false
+	}
+	return true
+}
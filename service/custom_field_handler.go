@@ -0,0 +1,342 @@
+package service
+
+import (
+	"encoding/json"
+	"ginDatabaseMhs/customfield"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/rsql"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+// prefix nama custom field di parameter filter dan columns export, contoh custom.hostel_room
+const customFieldPrefix = "custom."
+
+func (h *Handler) CustomFieldList(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	// admin bisa melihat definisi milik pemilik lain lewat owner_id
+	ownerID := userID
+	if isAdmin(ctx) {
+		id, err := queryInt64(ctx, "owner_id")
+		if err != nil {
+			abortQueryError(ctx, err)
+			return
+		}
+		if id != 0 {
+			ownerID = id
+		}
+	}
+	fields, ok := h.customFields(ctx, ownerID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Success Get Custom Fields",
+		Data:    fields,
+	})
+}
+
+func (h *Handler) CustomFieldCreate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	if !requireAdmin(ctx, "Only admin can define custom fields") {
+		return
+	}
+
+	var req request.CustomFieldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	// definisi dibuat untuk pemilik data, nilai custom field divalidasi terhadap definisi milik pemilik data
+	ownerID := req.OwnerID
+	if ownerID == 0 {
+		ownerID = userID
+	} else {
+		owners, err := h.MahasiswaRepository.GetUsersByIDs([]int64{ownerID})
+		if err != nil {
+			logrus.Errorf("failed when get owner: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+				Message: "Internal Server Error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+		if len(owners) == 0 {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+				Message: "Owner not found",
+				Status:  http.StatusNotFound,
+			})
+			return
+		}
+	}
+
+	field := &entity.CustomField{
+		UserID:   ownerID,
+		Key:      strings.TrimSpace(req.Key),
+		Label:    strings.TrimSpace(req.Label),
+		Type:     req.Type,
+		Required: req.Required,
+		Options:  entity.StringList(trimOptions(req.Options)),
+		Pattern:  req.Pattern,
+	}
+	if err := customfield.CheckDefinition(*field); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	existing, err := h.MahasiswaRepository.GetCustomFieldByKey(ownerID, field.Key)
+	if err != nil {
+		logrus.Errorf("failed when checking custom field key: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if existing != nil {
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "Custom field with the same key already exists",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	if err := h.MahasiswaRepository.CreateCustomField(field); err != nil {
+		logrus.Errorf("failed when creating custom field: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, request.SuccessMessage{
+		Status:  http.StatusCreated,
+		Message: "Custom Field Created",
+		Data:    field,
+	})
+}
+
+func (h *Handler) CustomFieldUpdate(ctx *gin.Context) {
+	if _, ok := userIDFromContext(ctx); !ok {
+		return
+	}
+	if !requireAdmin(ctx, "Only admin can define custom fields") {
+		return
+	}
+
+	var req request.CustomFieldUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	field, ok := h.findCustomField(ctx)
+	if !ok {
+		return
+	}
+
+	field.Label = strings.TrimSpace(req.Label)
+	field.Required = req.Required
+	field.Options = entity.StringList(trimOptions(req.Options))
+	field.Pattern = req.Pattern
+	if err := customfield.CheckDefinition(*field); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	_, err := h.MahasiswaRepository.UpdateCustomField(field.ID, field.UserID, map[string]interface{}{
+		"label":    field.Label,
+		"required": field.Required,
+		"options":  field.Options,
+		"pattern":  field.Pattern,
+	})
+	if err != nil {
+		logrus.Errorf("failed when updating custom field: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Custom Field Updated",
+		Data:    field,
+	})
+}
+
+func (h *Handler) CustomFieldDelete(ctx *gin.Context) {
+	if _, ok := userIDFromContext(ctx); !ok {
+		return
+	}
+	if !requireAdmin(ctx, "Only admin can define custom fields") {
+		return
+	}
+	field, ok := h.findCustomField(ctx)
+	if !ok {
+		return
+	}
+
+	if _, err := h.MahasiswaRepository.DeleteCustomField(field.ID, field.UserID); err != nil {
+		logrus.Errorf("failed when deleting custom field: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Custom Field Deleted",
+	})
+}
+
+// findCustomField definisi dari path parameter id, hanya dipakai admin jadi tidak dibatasi pemilik
+func (h *Handler) findCustomField(ctx *gin.Context) (*entity.CustomField, bool) {
+	fieldID, ok := paramID(ctx, "id")
+	if !ok {
+		return nil, false
+	}
+
+	field, err := h.MahasiswaRepository.GetCustomFieldByID(fieldID)
+	if err != nil {
+		logrus.Errorf("failed when get custom field: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if field == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Custom field not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+	return field, true
+}
+
+// customFields definisi custom field milik ownerID, response error langsung dikirim kalau gagal
+func (h *Handler) customFields(ctx *gin.Context, ownerID int64) ([]entity.CustomField, bool) {
+	fields, err := h.MahasiswaRepository.GetCustomFieldsByUser(ownerID)
+	if err != nil {
+		logrus.Errorf("failed when get custom fields: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	return fields, true
+}
+
+// customFieldValues menggabungkan input ke nilai lama lalu memvalidasinya terhadap definisi milik ownerID
+func (h *Handler) customFieldValues(ctx *gin.Context, ownerID int64, current entity.JSON, input map[string]interface{}) (entity.JSON, bool) {
+	fields, ok := h.customFields(ctx, ownerID)
+	if !ok {
+		return nil, false
+	}
+
+	merged, err := customfield.Merge(fields, current, input)
+	if err != nil {
+		logrus.Errorf("failed when reading custom fields: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	values, errs := customfield.Validate(fields, merged)
+	if len(errs) > 0 {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid custom fields: " + strings.Join(errs, "; "),
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+	if len(values) == 0 {
+		return nil, true
+	}
+
+	raw, err := json.Marshal(values)
+	if err != nil {
+		logrus.Errorf("failed when encoding custom fields: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	return entity.JSON(raw), true
+}
+
+// customFilterSchema schema filter data mahasiswa ditambah custom field dengan prefix custom.
+func customFilterSchema(fields []entity.CustomField) rsql.Schema {
+	schema := make(rsql.Schema, len(mahasiswaFilterSchema)+len(fields))
+	for name, field := range mahasiswaFilterSchema {
+		schema[name] = field
+	}
+	for _, field := range fields {
+		filterField := rsql.Field{Column: customfield.Column(field), Type: rsql.String, Operators: rsql.StringOperators}
+		switch field.Type {
+		case customfield.TypeNumber:
+			filterField.Type, filterField.Operators = rsql.Float, rsql.OrderOperators
+		case customfield.TypeDate:
+			filterField.Type, filterField.Operators = rsql.Date, rsql.OrderOperators
+		case customfield.TypeBoolean:
+			filterField.Type, filterField.Operators = rsql.Bool, rsql.BoolOperators
+		}
+		schema[customFieldPrefix+field.Key] = filterField
+	}
+	return schema
+}
+
+// requireAdmin mengirim 403 kalau user bukan admin
+func requireAdmin(ctx *gin.Context, message string) bool {
+	if isAdmin(ctx) {
+		return true
+	}
+	ctx.AbortWithStatusJSON(http.StatusForbidden, respErr.ErrorResponse{
+		Message: message,
+		Status:  http.StatusForbidden,
+	})
+	return false
+}
+
+func trimOptions(options []string) []string {
+	var out []string
+	for _, option := range options {
+		if option = strings.TrimSpace(option); option != "" && !containsString(out, option) {
+			out = append(out, option)
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"encoding/json"
+	"ginDatabaseMhs/audit"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/repository"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// customFieldRepo repository palsu untuk handler custom field, method lain panic karena tidak dipakai
+type customFieldRepo struct {
+	repository.MahasiswaRepository
+	fields  []entity.CustomField
+	created *entity.User_data
+}
+
+func (r *customFieldRepo) GetUsersByIDs(ids []int64) ([]entity.User, error) {
+	return []entity.User{{ID: ids[0], Role: entity.RoleUser}}, nil
+}
+
+func (r *customFieldRepo) GetCustomFieldByKey(userID int64, key string) (*entity.CustomField, error) {
+	for _, field := range r.fields {
+		if field.UserID == userID && field.Key == key {
+			return &field, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *customFieldRepo) CreateCustomField(field *entity.CustomField) error {
+	field.ID = int64(len(r.fields) + 1)
+	r.fields = append(r.fields, *field)
+	return nil
+}
+
+func (r *customFieldRepo) GetCustomFieldsByUser(userID int64) ([]entity.CustomField, error) {
+	var fields []entity.CustomField
+	for _, field := range r.fields {
+		if field.UserID == userID {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+func (r *customFieldRepo) GetMahasiswaByNameAndEmail(name, email string) (*entity.User_data, error) {
+	return nil, nil
+}
+
+func (r *customFieldRepo) Create(mahasiswa *entity.User_data, actor audit.Actor) (*entity.User_data, error) {
+	mahasiswa.ID = 1
+	r.created = mahasiswa
+	return mahasiswa, nil
+}
+
+func serveAs(handler gin.HandlerFunc, userID int64, role, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Set("user_id", userID)
+	ctx.Set("role", role)
+	handler(ctx)
+	return rec
+}
+
+func TestCustomFieldForOwner(t *testing.T) {
+	const adminID, ownerID = 1, 7
+	repo := &customFieldRepo{}
+	h := &Handler{MahasiswaRepository: repo}
+
+	rec := serveAs(h.CustomFieldCreate, adminID, entity.RoleAdmin,
+		`{"owner_id": 7, "key": "hostel_room", "label": "Hostel Room", "type": "text", "required": true}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create definition: status %d, body %s", rec.Code, rec.Body)
+	}
+	if len(repo.fields) != 1 || repo.fields[0].UserID != ownerID {
+		t.Fatalf("definition should belong to owner %d, got %+v", ownerID, repo.fields)
+	}
+
+	rec = serveAs(h.CustomFieldCreate, ownerID, entity.RoleUser,
+		`{"key": "nickname", "label": "Nickname", "type": "text"}`)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("non admin create definition: status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	rec = serveAs(h.HandlerCreate, ownerID, entity.RoleUser,
+		`{"name": "Budi", "email": "budi@gmail.com", "custom_fields": {"hostel_room": "A-12"}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("owner save value: status %d, body %s", rec.Code, rec.Body)
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(repo.created.CustomFields, &values); err != nil {
+		t.Fatal(err)
+	}
+	if values["hostel_room"] != "A-12" {
+		t.Fatalf("custom field value not saved, got %v", values)
+	}
+
+	rec = serveAs(h.HandlerCreate, ownerID, entity.RoleUser, `{"name": "Siti", "email": "siti@gmail.com"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing required value: status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -1,31 +1,54 @@
 package service
 
 import (
-	"errors"
 	"fmt"
+	"ginDatabaseMhs/cache"
 	"ginDatabaseMhs/cfg"
+	"ginDatabaseMhs/gpa"
+	"ginDatabaseMhs/imaging"
 	"ginDatabaseMhs/model/entity"
 	"ginDatabaseMhs/model/request"
 	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/pagination"
 	"ginDatabaseMhs/repository"
+	"ginDatabaseMhs/search"
+	"ginDatabaseMhs/storage"
+	"ginDatabaseMhs/upload"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"net/http"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Handler struct {
 	MahasiswaRepository repository.MahasiswaRepository
+	SearchIndex         search.Index
+	Storage             *storage.Set
+	UploadPolicy        upload.Policy
+	ImageProcessor      imaging.Processor
+	PresignExpiry       time.Duration
+	GradeScale          gpa.Scale
+	// AttendanceThreshold persentase kehadiran minimal (0-100)
+	AttendanceThreshold float64
+	StatsCache          *cache.TTL[request.StatsResponse]
 }
 
-func NewMahasiswaService(mahasiswaRepo repository.MahasiswaRepository) *Handler {
+func NewMahasiswaService(mahasiswaRepo repository.MahasiswaRepository, searchIndex search.Index, store *storage.Set, uploadPolicy upload.Policy, imageProcessor imaging.Processor, presignExpiry time.Duration, gradeScale gpa.Scale, attendanceThreshold float64, statsCacheTTL time.Duration) *Handler {
 	return &Handler{
 		MahasiswaRepository: mahasiswaRepo,
+		SearchIndex:         searchIndex,
+		Storage:             store,
+		UploadPolicy:        uploadPolicy,
+		ImageProcessor:      imageProcessor,
+		PresignExpiry:       presignExpiry,
+		GradeScale:          gradeScale,
+		AttendanceThreshold: attendanceThreshold,
+		StatsCache:          cache.NewTTL[request.StatsResponse](statsCacheTTL),
 	}
 }
 
@@ -69,12 +92,13 @@ func (h *Handler) Register(ctx *gin.Context) {
 		return
 	}
 
-	// simpan pengguna ke database
+	// simpan pengguna ke database, role dari request diabaikan supaya
+	// tidak ada yang bisa mendaftar langsung sebagai admin
 	newUser := &entity.User{
 		Username: user.Username,
 		Password: string(hashedPassword),
 		Email:    user.Email,
-		Role:     user.Role,
+		Role:     entity.RoleUser,
 	}
 	err = h.MahasiswaRepository.CreateUser(newUser)
 	if err != nil {
@@ -161,8 +185,27 @@ func (h *Handler) Access(ctx *gin.Context) {
 
 // Handler untuk menampilkan semua data pengguna
 func (h *Handler) ViewAllUsers(ctx *gin.Context) {
+	// filter opsional, contoh: role==user;username=like=*rey*
+	var where clause.Expression
+	if raw := ctx.Query("filter"); raw != "" {
+		var err error
+		if where, err = userFilterSchema.ParseAndCompile(raw); err != nil {
+			abortFilterError(ctx, err)
+			return
+		}
+	}
+	sort, err := parseSort(ctx.Query("sort"), userSortColumns)
+	if err != nil {
+		abortQueryError(ctx, err)
+		return
+	}
+	params, ok := keysetParams(ctx, userKeys(sort))
+	if !ok {
+		return
+	}
+
 	// Dapatkan data pengguna dari basis data
-	users, err := h.MahasiswaRepository.GetAllUsers()
+	users, err := h.MahasiswaRepository.GetAllUsers(where, params)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, respErr.ErrorResponse{
 			Message: "Internal Server Error",
@@ -171,8 +214,16 @@ func (h *Handler) ViewAllUsers(ctx *gin.Context) {
 		return
 	}
 
+	users, page := pagination.Paginate(params, users, userCursorValues(sort))
+	setLinkHeader(ctx, page)
+
 	// Tampilkan data pengguna dalam format respons JSON
-	ctx.JSON(http.StatusOK, users)
+	ctx.JSON(http.StatusOK, request.ListResponse{
+		Status:  http.StatusOK,
+		Message: "Success Get Users",
+		Data:    users,
+		Paging:  &page,
+	})
 }
 
 // Handler untuk menghapus pengguna dengan peran "user"
@@ -228,7 +279,31 @@ func (h *Handler) HandlerGetAll(ctx *gin.Context) {
 		return
 	}
 
-	lod, err := h.MahasiswaRepository.GetAllUserByID(userIDInt64)
+	fields, ok := h.customFields(ctx, userIDInt64)
+	if !ok {
+		return
+	}
+	filter, err := parseMahasiswaFilter(ctx, customFilterSchema(fields))
+	if err != nil {
+		abortFilterError(ctx, err)
+		return
+	}
+	sort, err := parseSort(ctx.Query("sort"), sortableColumns)
+	if err != nil {
+		abortQueryError(ctx, err)
+		return
+	}
+	fs, err := parseFieldset(ctx)
+	if err != nil {
+		abortQueryError(ctx, err)
+		return
+	}
+	params, ok := keysetParams(ctx, mahasiswaKeys(sort))
+	if !ok {
+		return
+	}
+
+	lod, err := h.MahasiswaRepository.GetAllUserByID(userIDInt64, filter, params, fs.projection(sort))
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, &respErr.ErrorResponse{
 			Message: err.Error(),
@@ -237,13 +312,17 @@ func (h *Handler) HandlerGetAll(ctx *gin.Context) {
 		return
 	}
 
+	lod, page := pagination.Paginate(params, lod, mahasiswaCursorValues(sort))
+	setLinkHeader(ctx, page)
+
 	logrus.Info(http.StatusOK, " Success Get All Data")
 	logrus.Info(userID)
 	ctx.AbortWithStatusJSON(http.StatusOK, request.ResponseToGetAll{
 		Message: "Success Get All",
 		UserId:  userIDInt64,
 		Data:    len(lod),
-		MHS:     lod,
+		MHS:     fs.renderAll(lod),
+		Paging:  &page,
 	})
 
 }
@@ -290,6 +369,21 @@ func (h *Handler) HandlerCreate(ctx *gin.Context) {
 		return
 	}
 
+	// Validasi dan normalisasi birthdate, age dan phone number
+	prof, err := normalizeProfile(data.Birthdate, data.Age, data.PhoneNumber)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	customFields, ok := h.customFieldValues(ctx, userIDInt64, nil, data.CustomFields)
+	if !ok {
+		return
+	}
+
 	// Check if data with the same name and email already exists
 	existingData, err := h.MahasiswaRepository.GetMahasiswaByNameAndEmail(data.Name, data.Email)
 	if err != nil {
@@ -309,16 +403,17 @@ func (h *Handler) HandlerCreate(ctx *gin.Context) {
 
 	//
 	newData := &entity.User_data{
-		UserID:      data.UserID,
-		Name:        data.Name,
-		Age:         data.Age,
-		Address:     data.Address,
-		Email:       data.Email,
-		Birthdate:   data.Birthdate,
-		PhoneNumber: data.PhoneNumber,
+		UserID:       data.UserID,
+		Name:         data.Name,
+		Age:          prof.Age,
+		Address:      data.Address,
+		Email:        data.Email,
+		Birthdate:    prof.Birthdate,
+		PhoneNumber:  prof.PhoneNumber,
+		CustomFields: customFields,
 	}
 
-	createdData, errCreate := h.MahasiswaRepository.Create(newData)
+	createdData, errCreate := h.MahasiswaRepository.Create(newData, auditActor(ctx))
 	if errCreate != nil {
 		ctx.JSON(http.StatusInternalServerError, respErr.ErrorResponse{
 			Message: "Internal Server Error",
@@ -368,7 +463,12 @@ func (h *Handler) HandlerGetByID(ctx *gin.Context) {
 		})
 		return
 	}
-	mhs, err := h.MahasiswaRepository.GetByID(mhsID, userIDInt64)
+	fs, err := parseFieldset(ctx)
+	if err != nil {
+		abortQueryError(ctx, err)
+		return
+	}
+	mhs, err := h.MahasiswaRepository.GetProjectedByID(mhsID, userIDInt64, fs.projection(nil))
 	if err != nil {
 		logrus.Errorf("failed when get todo by id: %v", err)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
@@ -388,7 +488,7 @@ func (h *Handler) HandlerGetByID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, request.MahasiswaResponse{
 		Status:  http.StatusOK,
 		Message: "Success Get Id",
-		Data:    *mhs,
+		Data:    fs.render(*mhs),
 	})
 }
 
@@ -434,6 +534,14 @@ func (h *Handler) HandlerUpdate(ctx *gin.Context) {
 		})
 		return
 	}
+	prof, err := normalizeProfile(reqBody.Birthdate, reqBody.Age, reqBody.PhoneNumber)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
 	ErrId, err := h.MahasiswaRepository.GetByID(mhsID, userIDInt64)
 	if err != nil {
 		logrus.Errorf("failed when get todo by id: %v", err)
@@ -450,7 +558,28 @@ func (h *Handler) HandlerUpdate(ctx *gin.Context) {
 		})
 		return
 	}
-	rowsAffected, err := h.MahasiswaRepository.Update(mhsID, userIDInt64, reqBody.ReqMhs())
+	if !h.canEdit(ctx, ErrId, userIDInt64) {
+		return
+	}
+	updates := reqBody.ReqMhs()
+	if !prof.Birthdate.IsZero() {
+		updates["birthdate"] = prof.Birthdate
+		updates["age"] = prof.Age
+	} else if prof.Age != 0 {
+		updates["age"] = prof.Age
+	}
+	if prof.PhoneNumber != "" {
+		updates["phone_number"] = prof.PhoneNumber
+	}
+	// custom field hanya divalidasi ulang kalau ikut dikirim, memakai definisi milik pemilik data
+	if reqBody.CustomFields != nil {
+		customFields, ok := h.customFieldValues(ctx, ErrId.UserID, ErrId.CustomFields, reqBody.CustomFields)
+		if !ok {
+			return
+		}
+		updates["custom_fields"] = customFields
+	}
+	rowsAffected, err := h.MahasiswaRepository.Update(mhsID, userIDInt64, updates, auditActor(ctx))
 	if err != nil {
 		logrus.Errorf("failed when updating data: %v", err)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
@@ -510,7 +639,7 @@ func (h *Handler) HandlerDelete(ctx *gin.Context) {
 	}
 
 	// Delete the Data with the specified mhsID and userID
-	isDeleted, err := h.MahasiswaRepository.Delete(mhsID, userIDInt64)
+	isDeleted, err := h.MahasiswaRepository.Delete(mhsID, userIDInt64, auditActor(ctx))
 	if err != nil {
 		logrus.Errorf("failed when deleting todo: %v", err)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
@@ -535,8 +664,7 @@ func (h *Handler) HandlerDelete(ctx *gin.Context) {
 	})
 }
 
-func (h *Handler) UploadFileS3AtchHandler(ctx *gin.Context) {
-	// Get the user ID from the token
+func (h *Handler) SearchHandler(ctx *gin.Context) {
 	userID, _ := ctx.Get("user_id")
 	if userID == nil {
 		logrus.Error("User not authenticated")
@@ -547,7 +675,7 @@ func (h *Handler) UploadFileS3AtchHandler(ctx *gin.Context) {
 		return
 	}
 
-	// Cast the userID to int64
+	// Cast userID ke int64
 	userIDInt64, ok := userID.(int64)
 	if !ok {
 		logrus.Error("Invalid user_id")
@@ -558,269 +686,94 @@ func (h *Handler) UploadFileS3AtchHandler(ctx *gin.Context) {
 		return
 	}
 
-	mhsIDStr := ctx.Param("id")
-	mhsID, err := strconv.ParseInt(mhsIDStr, 10, 64)
-	if err != nil {
-		logrus.Error(err)
-		ctx.JSON(http.StatusBadRequest, respErr.ErrorResponse{
-			Message: err.Error(),
-			Status:  http.StatusBadRequest,
-		})
-		return
-	}
-
-	// Check if the Data with the given ID exists
-	mhs, err := h.MahasiswaRepository.GetByID(mhsID, userIDInt64)
-	if err != nil {
-		ctx.JSON(http.StatusNotFound, respErr.ErrorResponse{
-			Message: "Todo not found",
-			Status:  http.StatusNotFound,
-		})
-		return
-	}
-
-	file, err := ctx.FormFile("file")
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, respErr.ErrorResponse{
-			Message: "No File Upload",
-			Status:  http.StatusBadRequest,
-		})
-		return
-	}
-
-	// Check file type yang boleh cuman jpg jpeg png webp
-	allowedExtensions := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".webp": true,
-	}
-	ext := filepath.Ext(file.Filename)
-	if !allowedExtensions[ext] {
-		ctx.JSON(http.StatusBadRequest, respErr.ErrorResponse{
-			Message: "error File not allowed type",
-			Status:  http.StatusBadRequest,
-		})
+	// Dapatkan parameter search dan filter dari query string
+	fields, ok := h.customFields(ctx, userIDInt64)
+	if !ok {
 		return
 	}
-
-	// Use the mahasiswaRepository to upload the file to S3
-	attachment, err := h.MahasiswaRepository.UploadFileS3Atch(file, mhsID, userIDInt64)
+	filter, err := parseMahasiswaFilter(ctx, customFilterSchema(fields))
 	if err != nil {
-		// Periksa apakah error merupakan "Data not found" atau bukan
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// Jika error disebabkan oleh record not found, kirim respons 404
-			ctx.JSON(http.StatusNotFound, respErr.ErrorResponse{
-				Message: "data mahasiswa not found",
-				Status:  http.StatusNotFound,
-			})
-		} else {
-			// Jika error bukan karena record not found, kirim respons 500
-			ctx.JSON(http.StatusInternalServerError, respErr.ErrorResponse{
-				Message: err.Error(),
-				Status:  http.StatusInternalServerError,
-			})
-			logrus.Error(err)
-		}
+		abortFilterError(ctx, err)
 		return
 	}
-
-	// Update the data_Mahasiswa Attachments field with the new attachment
-	mhs.Attachments = append(mhs.Attachments, *attachment)
-
-	// Create an attachment record in the database
-	err = h.MahasiswaRepository.UpdateWithAttachments(mhs)
+	sort, err := parseSort(ctx.Query("sort"), sortableColumns)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, respErr.ErrorResponse{
-			Message: "Failed to update Todo with attachments",
-			Status:  http.StatusInternalServerError,
-		})
+		abortQueryError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, request.SuccessMessage{
-		Message: "File uploaded and attachment created successfully",
-		Data:    attachment,
-		Status:  http.StatusOK,
-	})
-}
-
-func (h *Handler) UploadFileS3BucketsHandler(ctx *gin.Context) {
-	file, err := ctx.FormFile("file")
+	fs, err := parseFieldset(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "No File Upload",
-		})
+		abortQueryError(ctx, err)
 		return
 	}
 
-	src, err := file.Open()
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to open file",
-		})
+	// tanpa parameter page pakai cursor, page/per_page sudah deprecated dan hanya untuk client lama
+	if ctx.Query("page") == "" {
+		h.searchByCursor(ctx, userIDInt64, filter, sort, fs)
 		return
 	}
-	defer src.Close()
+	ctx.Header("Deprecation", "true")
 
-	// Use the mhsRepository to upload the file to S3
-	publicURL, err := h.MahasiswaRepository.UploadFileS3Buckets(src, file.Filename)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to upload file to S3",
-		})
-		return
+	// Dapatkan parameter page dan per_page dari query string
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(ctx.DefaultQuery("per_page", "10"))
+	if page < 1 {
+		page = 1
 	}
-
-	ctx.JSON(http.StatusOK, gin.H{
-		"message": "File uploaded to S3 successfully",
-		"url":     *publicURL,
-	})
-
-}
-
-func (h *Handler) UploadLocalAtchHandler(ctx *gin.Context) {
-
-	// Get the userID from the token
-	userID, _ := ctx.Get("user_id")
-	if userID == nil {
-		logrus.Error("User not authenticated")
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, respErr.ErrorResponse{
-			Message: "User not authenticated",
-			Status:  http.StatusUnauthorized,
-		})
-		return
+	if perPage < 1 || perPage > 100 {
+		perPage = 10
 	}
-
-	// Cast the userID to int64
-	userIDInt64, ok := userID.(int64)
-	if !ok {
-		logrus.Error("Invalid user_id")
+	// OFFSET makin lambat di halaman yang dalam, halaman setelah batas harus memakai cursor
+	if page > maxOffsetPage {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
-			Message: "Invalid user_id",
+			Message: fmt.Sprintf("page must not exceed %d, use cursor pagination (omit page) for deeper results", maxOffsetPage),
 			Status:  http.StatusBadRequest,
 		})
 		return
 	}
 
-	mhsIDStr := ctx.Param("id")
-	mhsID, err := strconv.ParseInt(mhsIDStr, 10, 64)
+	dataMhs, total, err := h.MahasiswaRepository.SearchMahasiswaByUser(userIDInt64, filter, sort, page, perPage, fs.projection(sort))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, respErr.ErrorResponse{
-			Message: "Invalid Data ID",
-			Status:  http.StatusBadRequest,
-		})
-		return
-	}
-
-	// Check if the Data with given ID exists
-	mhs, err := h.MahasiswaRepository.GetByID(mhsID, userIDInt64)
-	if err != nil {
-		ctx.JSON(http.StatusNotFound, respErr.ErrorResponse{
-			Message: "data mahasiswa not found",
-			Status:  http.StatusBadRequest,
+		logrus.Errorf("failed when searching data mhs: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
 		})
 		return
 	}
 
-	file, err := ctx.FormFile("file")
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, respErr.ErrorResponse{
-			Message: "No FIle Upload",
-			Status:  http.StatusBadRequest,
-		})
-		return
+	// Membuat respons dengan data hasil pencarian
+	response := request.SearchResponse{
+		Status:     http.StatusOK,
+		Data:       fs.renderAll(dataMhs),
+		Total:      total,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: int((total + int64(perPage) - 1) / int64(perPage)),
 	}
 
-	// Check file
-	allowedExtensions := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".webp": true,
-	}
-	ext := filepath.Ext(file.Filename)
-	if !allowedExtensions[ext] {
-		ctx.JSON(http.StatusBadRequest, respErr.ErrorResponse{
-			Message: "error not allowed type",
-			Status:  http.StatusBadRequest,
-		})
-		return
-	}
+	ctx.JSON(http.StatusOK, response)
+}
 
-	// use
-	attachment, err := h.MahasiswaRepository.UploadFileLocalAtch(file, mhsID, userIDInt64)
-	if err != nil {
-		// Periksa apakah error merupakan "data mahasiswa not found" atau bukan
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// Jika error disebabkan oleh record not found, kirim respons 404
-			ctx.JSON(http.StatusNotFound, respErr.ErrorResponse{
-				Message: "data mahasiswa not found",
-				Status:  http.StatusNotFound,
-			})
-		} else {
-			// Jika error bukan karena record not found, kirim respons 500
-			ctx.JSON(http.StatusInternalServerError, respErr.ErrorResponse{
-				Message: err.Error(),
-				Status:  http.StatusInternalServerError,
-			})
-			logrus.Error(err)
-		}
+func (h *Handler) searchByCursor(ctx *gin.Context, userID int64, filter request.MahasiswaFilter, sort []request.SortField, fs fieldset) {
+	params, ok := keysetParams(ctx, mahasiswaKeys(sort))
+	if !ok {
 		return
 	}
 
-	// Update the data Attachments field with the new attachment
-	mhs.Attachments = append(mhs.Attachments, *attachment)
-
-	// Save the updated data to the database
-	err = h.MahasiswaRepository.UpdatetoAtch(mhs)
+	dataMhs, err := h.MahasiswaRepository.GetAllUserByID(userID, filter, params, fs.projection(sort))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, respErr.ErrorResponse{
-			Message: err.Error(),
+		logrus.Errorf("failed when searching data mhs: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
 			Status:  http.StatusInternalServerError,
 		})
 		return
 	}
-
-	ctx.JSON(http.StatusOK, request.SuccessMessage{
-		Status:  http.StatusOK,
-		Message: "FIle Uploaded and attachment created successfully",
-		Data:    attachment,
-	})
-}
-
-func (h *Handler) SearchHandler(ctx *gin.Context) {
-	userID, _ := ctx.Get("user_id")
-	if userID == nil {
-		logrus.Error("User not authenticated")
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, respErr.ErrorResponse{
-			Message: "User not authenticated",
-			Status:  http.StatusUnauthorized,
-		})
-		return
-	}
-
-	// Cast userID ke int64
-	userIDInt64, ok := userID.(int64)
-	if !ok {
-		logrus.Error("Invalid user_id")
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
-			Message: "Invalid user_id",
-			Status:  http.StatusBadRequest,
-		})
-		return
-	}
-
-	// Dapatkan parameter search dari query string
-	search := ctx.Query("search")
-
-	// Dapatkan parameter page dan per_page dari query string
-	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(ctx.DefaultQuery("per_page", "10"))
-
-	dataMhs, total, err := h.MahasiswaRepository.SearchMahasiswaByUser(userIDInt64, search, page, perPage)
+	total, err := h.MahasiswaRepository.CountByUser(userID, filter)
 	if err != nil {
-		logrus.Errorf("failed when searching data mhs: %v", err)
+		logrus.Errorf("failed when counting data mhs: %v", err)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
 			Message: "Internal Server Error",
 			Status:  http.StatusInternalServerError,
@@ -828,14 +781,15 @@ func (h *Handler) SearchHandler(ctx *gin.Context) {
 		return
 	}
 
-	// Membuat respons dengan data hasil pencarian
-	response := request.SearchResponse{
+	dataMhs, page := pagination.Paginate(params, dataMhs, mahasiswaCursorValues(sort))
+	setLinkHeader(ctx, page)
+
+	ctx.JSON(http.StatusOK, request.SearchResponse{
 		Status: http.StatusOK,
-		Data:   dataMhs,
+		Data:   fs.renderAll(dataMhs),
 		Total:  total,
-	}
-
-	ctx.JSON(http.StatusOK, response)
+		Paging: &page,
+	})
 }
 
 ///////////////////////////////////////////////////////////////////
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"ginDatabaseMhs/audit"
+	"ginDatabaseMhs/dedupe"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+// kolom yang dibutuhkan untuk mencari duplikat
+var duplicateColumns = []string{"id", "name", "email", "phone_number", "birthdate", "user_id"}
+
+func (h *Handler) DuplicatesHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	threshold := dedupe.DefaultThreshold
+	if raw := ctx.Query("threshold"); raw != "" {
+		t, err := strconv.ParseFloat(raw, 64)
+		if err != nil || t <= 0 || t > 1 {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+				Message: "threshold must be a number between 0 and 1",
+				Status:  http.StatusBadRequest,
+			})
+			return
+		}
+		threshold = t
+	}
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	var records []entity.User_data
+	err := h.MahasiswaRepository.StreamByUser(userID, request.MahasiswaFilter{}, duplicateColumns, func(data entity.User_data) error {
+		records = append(records, data)
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("failed when reading data for duplicates: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	pairs := dedupe.FindPairs(records, threshold)
+	total := len(pairs)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	ctx.JSON(http.StatusOK, request.DuplicatesResponse{
+		Status:     http.StatusOK,
+		Threshold:  threshold,
+		Total:      total,
+		Page:       page,
+		PerPage:    limit,
+		TotalPages: (total + limit - 1) / limit,
+		Data:       pairs[start:end],
+	})
+}
+
+func (h *Handler) MergeHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.MergeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	if req.SurvivorID == req.DuplicateID {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "survivor_id and duplicate_id must be different",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	for field, keep := range req.Fields {
+		if !containsString(revertableFields, field) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+				Message: fmt.Sprintf("field %q cannot be merged", field),
+				Status:  http.StatusBadRequest,
+			})
+			return
+		}
+		if keep != request.MergeKeepSurvivor && keep != request.MergeKeepDuplicate {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+				Message: fmt.Sprintf("field %q must be %q or %q", field, request.MergeKeepSurvivor, request.MergeKeepDuplicate),
+				Status:  http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	survivor, err := h.MahasiswaRepository.GetByID(req.SurvivorID, userID)
+	if err != nil {
+		logrus.Errorf("failed when get survivor: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	duplicate, err := h.MahasiswaRepository.GetByID(req.DuplicateID, userID)
+	if err != nil {
+		logrus.Errorf("failed when get duplicate: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if survivor == nil || duplicate == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	updates, err := mergeUpdates(survivor, duplicate, req.Fields)
+	if err != nil {
+		logrus.Errorf("failed when building merge: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	merged, err := h.MahasiswaRepository.MergeMahasiswa(survivor.ID, duplicate.ID, userID, updates, auditActor(ctx))
+	if err != nil {
+		logrus.Errorf("failed when merging data: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if merged == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.MahasiswaResponse{
+		Status:  http.StatusOK,
+		Message: "Success Merge",
+		Data:    merged,
+	})
+}
+
+// mergeUpdates menentukan nilai akhir setiap field, nilai survivor yang kosong diisi dari duplicate,
+// termasuk nilai di custom_fields
+func mergeUpdates(survivor, duplicate *entity.User_data, choices map[string]string) (map[string]interface{}, error) {
+	survivorFields, err := audit.Fields(survivor)
+	if err != nil {
+		return nil, err
+	}
+	duplicateFields, err := audit.Fields(duplicate)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	for _, field := range revertableFields {
+		keep := choices[field]
+		if keep == "" && isEmptyValue(survivorFields[field]) {
+			keep = request.MergeKeepDuplicate
+		}
+		if keep == request.MergeKeepDuplicate && !isEmptyValue(duplicateFields[field]) {
+			updates[field] = duplicateFields[field]
+		}
+	}
+
+	customFields, changed, err := mergeCustomFields(survivor.CustomFields, duplicate.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		updates["custom_fields"] = customFields
+	}
+	return updates, nil
+}
+
+// mergeCustomFields nilai custom field survivor dipertahankan, key yang kosong di survivor diisi dari duplicate
+func mergeCustomFields(survivor, duplicate entity.JSON) (entity.JSON, bool, error) {
+	if len(duplicate) == 0 {
+		return survivor, false, nil
+	}
+	values := map[string]interface{}{}
+	if len(survivor) > 0 {
+		if err := json.Unmarshal(survivor, &values); err != nil {
+			return nil, false, err
+		}
+	}
+	var duplicateValues map[string]interface{}
+	if err := json.Unmarshal(duplicate, &duplicateValues); err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	for key, value := range duplicateValues {
+		if isEmptyValue(values[key]) && !isEmptyValue(value) {
+			values[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return survivor, false, nil
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, false, err
+	}
+	return entity.JSON(raw), true, nil
+}
+
+func isEmptyValue(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case float64:
+		return value == 0
+	}
+	return false
+}
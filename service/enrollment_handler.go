@@ -0,0 +1,341 @@
+package service
+
+import (
+	"fmt"
+	"ginDatabaseMhs/gpa"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+func (h *Handler) EnrollmentList(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+	if _, ok := h.findRecord(ctx, mhsID, userID); !ok {
+		return
+	}
+
+	enrollments, err := h.MahasiswaRepository.GetEnrollmentsByRecord(mhsID)
+	if err != nil {
+		logrus.Errorf("failed when get enrollments: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Success Get Enrollments",
+		Data:    enrollments,
+	})
+}
+
+func (h *Handler) EnrollmentCreate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req request.EnrollmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	mhs, ok := h.findRecord(ctx, mhsID, userID)
+	if !ok || !h.canEdit(ctx, mhs, userID) {
+		return
+	}
+	// mata kuliah dan semester harus milik pemilik data, bukan milik user yang diberi akses edit
+	course, ok := h.findCourse(ctx, req.CourseID, mhs.UserID)
+	if !ok {
+		return
+	}
+	semester, ok := h.findSemester(ctx, req.SemesterID, mhs.UserID)
+	if !ok {
+		return
+	}
+
+	existing, err := h.MahasiswaRepository.GetEnrollmentsByRecord(mhsID)
+	if err != nil {
+		logrus.Errorf("failed when get enrollments: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	for _, e := range existing {
+		if e.CourseID == course.ID && e.SemesterID == semester.ID {
+			ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+				Message: "Student is already enrolled in this course for the semester",
+				Status:  http.StatusConflict,
+			})
+			return
+		}
+	}
+
+	enrollment := &entity.Enrollment{
+		RecordID:   mhsID,
+		CourseID:   course.ID,
+		SemesterID: semester.ID,
+	}
+	if err := h.MahasiswaRepository.CreateEnrollment(enrollment); err != nil {
+		logrus.Errorf("failed when creating enrollment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	enrollment.Course = course
+	enrollment.Semester = semester
+
+	ctx.JSON(http.StatusCreated, request.SuccessMessage{
+		Status:  http.StatusCreated,
+		Message: "Enrollment Created",
+		Data:    enrollment,
+	})
+}
+
+func (h *Handler) EnrollmentDelete(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	enrollment, _, ok := h.editableEnrollment(ctx, userID)
+	if !ok {
+		return
+	}
+
+	if _, err := h.MahasiswaRepository.DeleteEnrollment(enrollment.ID); err != nil {
+		logrus.Errorf("failed when deleting enrollment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Enrollment Deleted",
+	})
+}
+
+func (h *Handler) GradeSave(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.GradeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	letter := strings.ToUpper(strings.TrimSpace(req.Letter))
+	if _, ok := h.GradeScale.Point(letter); !ok {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: fmt.Sprintf("unknown grade %q, allowed: %s", req.Letter, strings.Join(h.GradeScale.Letters(), ", ")),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	enrollment, mhs, ok := h.editableEnrollment(ctx, userID)
+	if !ok {
+		return
+	}
+	// nilai hanya bisa diisi untuk mata kuliah milik pemilik data
+	if enrollment.Course == nil || enrollment.Course.UserID != mhs.UserID {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Course not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	grade := &entity.Grade{
+		EnrollmentID: enrollment.ID,
+		Letter:       letter,
+		Score:        req.Score,
+	}
+	if err := h.MahasiswaRepository.SaveGrade(grade); err != nil {
+		logrus.Errorf("failed when saving grade: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Grade Saved",
+		Data:    grade,
+	})
+}
+
+func (h *Handler) GradeDelete(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	enrollment, _, ok := h.editableEnrollment(ctx, userID)
+	if !ok {
+		return
+	}
+
+	rowsAffected, err := h.MahasiswaRepository.DeleteGrade(enrollment.ID)
+	if err != nil {
+		logrus.Errorf("failed when deleting grade: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if rowsAffected == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Grade not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Grade Deleted",
+	})
+}
+
+// GPAHandler IP per semester dan IPK kumulatif satu mahasiswa
+func (h *Handler) GPAHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+	if _, ok := h.findRecord(ctx, mhsID, userID); !ok {
+		return
+	}
+
+	enrollments, err := h.MahasiswaRepository.GetEnrollmentsByRecord(mhsID)
+	if err != nil {
+		logrus.Errorf("failed when get enrollments: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	names := map[int64]string{}
+	entries := make([]gpa.Entry, 0, len(enrollments))
+	for _, e := range enrollments {
+		if e.Grade == nil || e.Course == nil || e.Semester == nil {
+			continue
+		}
+		names[e.SemesterID] = e.Semester.Name
+		entries = append(entries, gpa.Entry{
+			CourseID:      e.CourseID,
+			SemesterID:    e.SemesterID,
+			SemesterOrder: e.Semester.StartDate.Unix(),
+			Credits:       e.Course.Credits,
+			Letter:        e.Grade.Letter,
+		})
+	}
+
+	summaries, cumulative := h.GradeScale.Compute(entries)
+	semesters := make([]request.GPASemester, 0, len(summaries))
+	for _, s := range summaries {
+		semesters = append(semesters, request.GPASemester{SemesterSummary: s, Name: names[s.SemesterID]})
+	}
+
+	ctx.JSON(http.StatusOK, request.GPAResponse{
+		Status:     http.StatusOK,
+		RecordID:   mhsID,
+		Scale:      h.GradeScale,
+		Semesters:  semesters,
+		Cumulative: cumulative,
+	})
+}
+
+// findRecord data mahasiswa yang bisa dilihat user (pemilik atau yang diberi akses)
+func (h *Handler) findRecord(ctx *gin.Context, mhsID, userID int64) (*entity.User_data, bool) {
+	mhs, err := h.MahasiswaRepository.GetByID(mhsID, userID)
+	if err != nil {
+		logrus.Errorf("failed when get data by id: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if mhs == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+	return mhs, true
+}
+
+// editableEnrollment enrollment dari path parameter id beserta data mahasiswanya, user harus boleh
+// mengubah data mahasiswa tersebut
+func (h *Handler) editableEnrollment(ctx *gin.Context, userID int64) (*entity.Enrollment, *entity.User_data, bool) {
+	enrollmentID, ok := paramID(ctx, "id")
+	if !ok {
+		return nil, nil, false
+	}
+
+	enrollment, err := h.MahasiswaRepository.GetEnrollmentByID(enrollmentID)
+	if err != nil {
+		logrus.Errorf("failed when get enrollment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, nil, false
+	}
+	if enrollment == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Enrollment not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, nil, false
+	}
+
+	mhs, ok := h.findRecord(ctx, enrollment.RecordID, userID)
+	if !ok || !h.canEdit(ctx, mhs, userID) {
+		return nil, nil, false
+	}
+	return enrollment, mhs, true
+}
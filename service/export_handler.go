@@ -0,0 +1,267 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/phone"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/xuri/excelize/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kolom yang boleh di export, urutan ini juga dipakai sebagai default
+var exportColumns = []string{"id", "name", "email", "age", "address", "birthdate", "phone_number"}
+
+func (h *Handler) ExportHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(ctx.DefaultQuery("format", "csv"))
+	if format != "csv" && format != "xlsx" && format != "jsonl" {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "format must be csv, xlsx or jsonl",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	fields, ok := h.customFields(ctx, userID)
+	if !ok {
+		return
+	}
+	// custom field bisa di export dengan nama custom.<key>
+	allowed := append([]string{}, exportColumns...)
+	for _, field := range fields {
+		allowed = append(allowed, customFieldPrefix+field.Key)
+	}
+
+	columns := exportColumns
+	if raw := ctx.Query("columns"); raw != "" {
+		columns = nil
+		for _, col := range strings.Split(raw, ",") {
+			col = strings.TrimSpace(col)
+			if !containsString(allowed, col) {
+				ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+					Message: fmt.Sprintf("unknown column %q", col),
+					Status:  http.StatusBadRequest,
+				})
+				return
+			}
+			columns = append(columns, col)
+		}
+	}
+
+	filter, err := parseMahasiswaFilter(ctx, customFilterSchema(fields))
+	if err != nil {
+		abortFilterError(ctx, err)
+		return
+	}
+
+	filename := fmt.Sprintf("mahasiswa-%s.%s", time.Now().Format("20060102-150405"), format)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	switch format {
+	case "csv":
+		err = h.exportCSV(ctx, userID, filter, columns)
+	case "xlsx":
+		err = h.exportXLSX(ctx, userID, filter, columns)
+	case "jsonl":
+		err = h.exportJSONLines(ctx, userID, filter, columns)
+	}
+	if err != nil {
+		logrus.Errorf("failed when exporting data: %v", err)
+		if !ctx.Writer.Written() {
+			ctx.Header("Content-Disposition", "")
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+				Message: "Internal Server Error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+		// header sudah terkirim, jadi error hanya bisa di log
+		_ = ctx.Error(err)
+		ctx.Abort()
+	}
+}
+
+func (h *Handler) exportCSV(ctx *gin.Context, userID int64, filter request.MahasiswaFilter, columns []string) error {
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Status(http.StatusOK)
+
+	writer := csv.NewWriter(ctx.Writer)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+
+	count := 0
+	err := h.MahasiswaRepository.StreamByUser(userID, filter, selectColumns(columns), func(data entity.User_data) error {
+		record := make([]string, len(columns))
+		for i, col := range columns {
+			record[i] = spreadsheetValue(exportValue(data, col))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		// flush berkala supaya data langsung terkirim ke client
+		count++
+		if count%500 == 0 {
+			writer.Flush()
+			ctx.Writer.Flush()
+		}
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (h *Handler) exportJSONLines(ctx *gin.Context, userID int64, filter request.MahasiswaFilter, columns []string) error {
+	ctx.Header("Content-Type", "application/x-ndjson")
+	ctx.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(ctx.Writer)
+	count := 0
+	return h.MahasiswaRepository.StreamByUser(userID, filter, selectColumns(columns), func(data entity.User_data) error {
+		record := make(map[string]interface{}, len(columns))
+		for _, col := range columns {
+			record[col] = exportValue(data, col)
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+
+		count++
+		if count%500 == 0 {
+			ctx.Writer.Flush()
+		}
+		return nil
+	})
+}
+
+func (h *Handler) exportXLSX(ctx *gin.Context, userID int64, filter request.MahasiswaFilter, columns []string) error {
+	book := excelize.NewFile()
+	defer book.Close()
+
+	sheet := book.GetSheetName(0)
+	// stream writer menyimpan baris ke file sementara kalau datanya besar
+	stream, err := book.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, col := range columns {
+		header[i] = col
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	rowNumber := 1
+	err = h.MahasiswaRepository.StreamByUser(userID, filter, selectColumns(columns), func(data entity.User_data) error {
+		rowNumber++
+		// cell string dari stream writer selalu disimpan sebagai teks, tidak pernah dibaca sebagai formula
+		record := make([]interface{}, len(columns))
+		for i, col := range columns {
+			record[i] = exportValue(data, col)
+		}
+		cell, err := excelize.CoordinatesToCellName(1, rowNumber)
+		if err != nil {
+			return err
+		}
+		return stream.SetRow(cell, record)
+	})
+	if err != nil {
+		return err
+	}
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+
+	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	ctx.Status(http.StatusOK)
+	return book.Write(ctx.Writer)
+}
+
+// exportValue mengambil nilai kolom dari data mahasiswa
+func exportValue(data entity.User_data, column string) interface{} {
+	switch column {
+	case "id":
+		return data.ID
+	case "name":
+		return data.Name
+	case "email":
+		return data.Email
+	case "age":
+		return data.Age
+	case "address":
+		return data.Address
+	case "birthdate":
+		return data.Birthdate.String()
+	case "phone_number":
+		return data.PhoneNumber
+	}
+	if key := strings.TrimPrefix(column, customFieldPrefix); key != column && len(data.CustomFields) > 0 {
+		var values map[string]interface{}
+		if err := json.Unmarshal(data.CustomFields, &values); err == nil && values[key] != nil {
+			return values[key]
+		}
+	}
+	return ""
+}
+
+// spreadsheetValue mencegah formula injection di CSV: teks yang diawali karakter yang dibaca spreadsheet
+// sebagai formula diberi awalan ' supaya tampil sebagai teks biasa. Angka dan nomor telepon (+62...)
+// tidak diubah supaya file bisa diimport kembali, + atau - hanya di-escape kalau diikuti selain angka
+func spreadsheetValue(v interface{}) string {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '@', '\t', '\r':
+		return "'" + s
+	case '+', '-':
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return s
+		}
+		if _, err := phone.Normalize(s); err == nil {
+			return s
+		}
+		if len(s) == 1 || s[1] < '0' || s[1] > '9' {
+			return "'" + s
+		}
+	}
+	return s
+}
+
+// selectColumns kolom database untuk kolom export, semua custom field dibaca dari kolom custom_fields
+func selectColumns(columns []string) []string {
+	var selected []string
+	for _, col := range columns {
+		if strings.HasPrefix(col, customFieldPrefix) {
+			col = "custom_fields"
+		}
+		if !containsString(selected, col) {
+			selected = append(selected, col)
+		}
+	}
+	return selected
+}
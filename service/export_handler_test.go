@@ -0,0 +1,29 @@
+package service
+
+import "testing"
+
+func TestSpreadsheetValue(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{"Budi", "Budi"},
+		{"", ""},
+		{int64(21), "21"},
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tteks", "'\tteks"},
+		{"+SUM(A1)", "'+SUM(A1)"},
+		{"-cmd|' /C calc'!A0", "'-cmd|' /C calc'!A0"},
+		{"-", "'-"},
+		// angka dan nomor telepon tetap apa adanya supaya bisa diimport kembali
+		{"+6281234567890", "+6281234567890"},
+		{"-12.5", "-12.5"},
+		{"+1 415 555 2671", "+1 415 555 2671"},
+	}
+	for _, tt := range tests {
+		if got := spreadsheetValue(tt.value); got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
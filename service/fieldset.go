@@ -0,0 +1,126 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"github.com/gin-gonic/gin"
+	"strings"
+)
+
+// kolom user_data yang boleh dipilih lewat parameter fields
+var selectableFields = []string{"id", "name", "email", "age", "address", "birthdate", "phone_number", "user_id", "custom_fields"}
+
+// relasi yang bisa dimuat lewat parameter include
+var includableRelations = []string{"attachments", "tags"}
+
+// fieldset hasil parameter fields dan include, fields kosong berarti semua kolom
+type fieldset struct {
+	fields      []string
+	attachments bool
+	tags        bool
+}
+
+// parseFieldset membaca format "fields=name,email&include=attachments,tags"
+func parseFieldset(ctx *gin.Context) (fieldset, error) {
+	var fs fieldset
+	for _, field := range splitList(ctx.Query("fields")) {
+		if !containsString(selectableFields, field) {
+			return fs, fmt.Errorf("unknown field %q, allowed: %s", field, strings.Join(selectableFields, ", "))
+		}
+		if !containsString(fs.fields, field) {
+			fs.fields = append(fs.fields, field)
+		}
+	}
+	for _, relation := range splitList(ctx.Query("include")) {
+		if !containsString(includableRelations, relation) {
+			return fs, fmt.Errorf("cannot include %q, allowed: %s", relation, strings.Join(includableRelations, ", "))
+		}
+		switch relation {
+		case "attachments":
+			fs.attachments = true
+		case "tags":
+			fs.tags = true
+		}
+	}
+	return fs, nil
+}
+
+// projection kolom yang diambil dari database, id dan kolom sort selalu ikut
+// karena dibutuhkan untuk preload attachments dan cursor
+func (fs fieldset) projection(sort []request.SortField) request.Projection {
+	proj := request.Projection{Attachments: fs.attachments, Tags: fs.tags}
+	if len(fs.fields) == 0 {
+		return proj
+	}
+
+	proj.Fields = append(proj.Fields, fs.fields...)
+	for _, column := range []string{"id", "user_id"} {
+		if !containsString(proj.Fields, column) {
+			proj.Fields = append(proj.Fields, column)
+		}
+	}
+	for _, field := range sort {
+		if !containsString(proj.Fields, field.Column) {
+			proj.Fields = append(proj.Fields, field.Column)
+		}
+	}
+	return proj
+}
+
+// render hanya menampilkan field yang diminta, attachments dan tags hanya tampil kalau di include
+func (fs fieldset) render(data entity.User_data) interface{} {
+	if len(fs.fields) == 0 && fs.attachments && !fs.tags {
+		return data
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return data
+	}
+
+	out := make(map[string]json.RawMessage, len(fs.fields)+1)
+	if len(fs.fields) == 0 {
+		out = all
+	}
+	for _, field := range fs.fields {
+		out[field] = all[field]
+	}
+	if fs.attachments {
+		out["attachments"] = all["attachments"]
+	} else {
+		delete(out, "attachments")
+	}
+	if fs.tags {
+		out["tags"] = all["tags"]
+		if out["tags"] == nil {
+			out["tags"] = json.RawMessage("[]")
+		}
+	} else {
+		delete(out, "tags")
+	}
+	return out
+}
+
+func (fs fieldset) renderAll(data []entity.User_data) []interface{} {
+	out := make([]interface{}, 0, len(data))
+	for _, d := range data {
+		out = append(out, fs.render(d))
+	}
+	return out
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
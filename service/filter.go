@@ -0,0 +1,149 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/rsql"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kolom user_data yang boleh dipakai untuk sort
+var sortableColumns = []string{"id", "name", "email", "age", "address", "birthdate"}
+
+// whitelist field dan operator untuk parameter filter
+var mahasiswaFilterSchema = rsql.Schema{
+	"id":           {Column: "id", Type: rsql.Int, Operators: rsql.OrderOperators},
+	"name":         {Column: "name", Type: rsql.String, Operators: rsql.StringOperators},
+	"email":        {Column: "email", Type: rsql.String, Operators: rsql.StringOperators},
+	"age":          {Column: entity.AgeSQL, Type: rsql.Int, Operators: rsql.OrderOperators},
+	"address":      {Column: "address", Type: rsql.String, Operators: rsql.StringOperators},
+	"birthdate":    {Column: "birthdate", Type: rsql.Date, Operators: rsql.OrderOperators},
+	"phone_number": {Column: "phone_number", Type: rsql.String, Operators: rsql.StringOperators},
+}
+
+var userFilterSchema = rsql.Schema{
+	"id":       {Column: "id", Type: rsql.Int, Operators: rsql.OrderOperators},
+	"username": {Column: "username", Type: rsql.String, Operators: rsql.StringOperators},
+	"email":    {Column: "email", Type: rsql.String, Operators: rsql.StringOperators},
+	"role":     {Column: "role", Type: rsql.String, Operators: []string{"==", "!=", "=in=", "=out="}},
+}
+
+// parseMahasiswaFilter membaca filter search dari query string, schema dipakai untuk parameter filter
+func parseMahasiswaFilter(ctx *gin.Context, schema rsql.Schema) (request.MahasiswaFilter, error) {
+	filter := request.MahasiswaFilter{
+		Search: strings.TrimSpace(ctx.Query("search")),
+	}
+
+	var err error
+	if filter.AgeMin, err = queryInt(ctx, "age_min"); err != nil {
+		return filter, err
+	}
+	if filter.AgeMax, err = queryInt(ctx, "age_max"); err != nil {
+		return filter, err
+	}
+	if filter.BirthFrom, err = queryDate(ctx, "birth_from"); err != nil {
+		return filter, err
+	}
+	if filter.BirthTo, err = queryDate(ctx, "birth_to"); err != nil {
+		return filter, err
+	}
+	for _, name := range splitList(ctx.Query("tags")) {
+		filter.Tags = append(filter.Tags, strings.ToLower(name))
+	}
+	switch ctx.DefaultQuery("tag_match", "any") {
+	case "any":
+	case "all":
+		filter.TagsAll = true
+	default:
+		return filter, fmt.Errorf("tag_match must be any or all")
+	}
+	if raw := ctx.Query("filter"); raw != "" {
+		if filter.Where, err = schema.ParseAndCompile(raw); err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+// parseSort membaca format "-age,name", tanda minus berarti urutan menurun
+func parseSort(raw string, allowed []string) ([]request.SortField, error) {
+	var sort []request.SortField
+	if raw == "" {
+		return sort, nil
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		field := request.SortField{Column: part}
+		if strings.HasPrefix(part, "-") {
+			field = request.SortField{Column: part[1:], Desc: true}
+		}
+		if !containsString(allowed, field.Column) {
+			return nil, fmt.Errorf("cannot sort by %q, allowed: %s", field.Column, strings.Join(allowed, ", "))
+		}
+		sort = append(sort, field)
+	}
+	return sort, nil
+}
+
+func queryInt(ctx *gin.Context, key string) (*int, error) {
+	raw := ctx.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a number", key)
+	}
+	return &n, nil
+}
+
+func queryInt64(ctx *gin.Context, key string) (int64, error) {
+	raw := ctx.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", key)
+	}
+	return n, nil
+}
+
+func queryDate(ctx *gin.Context, key string) (string, error) {
+	raw := ctx.Query(key)
+	if raw == "" {
+		return "", nil
+	}
+	if _, err := time.Parse("2006-01-02", raw); err != nil {
+		return "", fmt.Errorf("%s must be a date in YYYY-MM-DD format", key)
+	}
+	return raw, nil
+}
+
+// abortFilterError mengirim response 400, error dari ekspresi filter ikut menyertakan posisinya
+func abortFilterError(ctx *gin.Context, err error) {
+	var filterErr *rsql.Error
+	if errors.As(err, &filterErr) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.FilterError{
+			Message:  err.Error(),
+			Status:   http.StatusBadRequest,
+			Filter:   ctx.Query("filter"),
+			Position: filterErr.Pos,
+		})
+		return
+	}
+
+	ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+		Message: err.Error(),
+		Status:  http.StatusBadRequest,
+	})
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func (h *Handler) FuzzySearchHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	query := strings.TrimSpace(ctx.Query("q"))
+	if query == "" {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "query parameter q is required",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	fs, err := parseFieldset(ctx)
+	if err != nil {
+		abortQueryError(ctx, err)
+		return
+	}
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	hits, err := h.SearchIndex.Search(userID, query, limit)
+	if err != nil {
+		logrus.Errorf("failed when searching index: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ids := make([]int64, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	dataMhs, err := h.MahasiswaRepository.GetByIDs(ids, userID, fs.projection(nil))
+	if err != nil {
+		logrus.Errorf("failed when get search result: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// urutan hasil mengikuti relevansi dari index
+	response := request.FuzzySearchResponse{
+		Status: http.StatusOK,
+		Query:  query,
+		Data:   make([]request.FuzzySearchHit, 0, len(hits)),
+	}
+	for _, hit := range hits {
+		for _, data := range dataMhs {
+			if data.ID == hit.ID {
+				response.Data = append(response.Data, request.FuzzySearchHit{
+					Score:      hit.Score,
+					Highlights: hit.Highlights,
+					Data:       fs.render(data),
+				})
+				break
+			}
+		}
+	}
+	response.Total = len(response.Data)
+
+	ctx.JSON(http.StatusOK, response)
+}
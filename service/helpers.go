@@ -0,0 +1,55 @@
+package service
+
+import (
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/respErr"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+// userIDFromContext mengambil user_id yang di set oleh Authmiddleware,
+// response error langsung dikirim kalau user_id tidak ada / tidak valid
+func userIDFromContext(ctx *gin.Context) (int64, bool) {
+	userID, _ := ctx.Get("user_id")
+	if userID == nil {
+		logrus.Error("User not authenticated")
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, respErr.ErrorResponse{
+			Message: "User not authenticated",
+			Status:  http.StatusUnauthorized,
+		})
+		return 0, false
+	}
+
+	userIDInt64, ok := userID.(int64)
+	if !ok {
+		logrus.Error("Invalid user_id")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid user_id",
+			Status:  http.StatusBadRequest,
+		})
+		return 0, false
+	}
+
+	return userIDInt64, true
+}
+
+// isAdmin role di set oleh Authmiddleware
+func isAdmin(ctx *gin.Context) bool {
+	return ctx.GetString("role") == entity.RoleAdmin
+}
+
+// paramID parse path parameter menjadi int64
+func paramID(ctx *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param(name), 10, 64)
+	if err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Parse ID Error",
+			Status:  http.StatusBadRequest,
+		})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"encoding/json"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+// field user_data yang bisa dikembalikan ke versi sebelumnya
+var revertableFields = []string{"name", "email", "age", "address", "birthdate", "phone_number"}
+
+func (h *Handler) HistoryHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	// data di trash tetap bisa dilihat history-nya
+	mhs, err := h.MahasiswaRepository.GetByIDWithTrashed(mhsID, userID)
+	if err != nil {
+		logrus.Errorf("failed when get data by id: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if mhs == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	// versi terbaru dulu
+	params, ok := keysetParams(ctx, []pagination.Key{{Expr: "id", Desc: true}})
+	if !ok {
+		return
+	}
+
+	logs, err := h.MahasiswaRepository.GetAuditLogsByRecord(mhsID, params)
+	if err != nil {
+		logrus.Errorf("failed when get history: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	logs, page := pagination.Paginate(params, logs, func(log entity.AuditLog) []interface{} {
+		return []interface{}{log.ID, log.ID}
+	})
+	setLinkHeader(ctx, page)
+
+	ctx.JSON(http.StatusOK, request.ListResponse{
+		Status:  http.StatusOK,
+		Message: "Success Get History",
+		Data:    logs,
+		Paging:  &page,
+	})
+}
+
+func (h *Handler) RevertHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid version",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	before, err := h.MahasiswaRepository.GetByID(mhsID, userID)
+	if err != nil {
+		logrus.Errorf("failed when get data by id: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if before == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	if !h.canEdit(ctx, before, userID) {
+		return
+	}
+
+	log, err := h.MahasiswaRepository.GetAuditLogVersion(entity.AuditEntityUserData, mhsID, version)
+	if err != nil {
+		logrus.Errorf("failed when get history version: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if log == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Version not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(log.Snapshot, &snapshot); err != nil {
+		logrus.Errorf("failed when reading snapshot: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	updates := make(map[string]interface{})
+	for _, field := range revertableFields {
+		if value, ok := snapshot[field]; ok {
+			updates[field] = value
+		}
+	}
+
+	reverted, err := h.MahasiswaRepository.Revert(mhsID, userID, updates, auditActor(ctx))
+	if err != nil {
+		logrus.Errorf("failed when reverting data: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if reverted == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	after, err := h.MahasiswaRepository.GetByID(mhsID, userID)
+	if err != nil || after == nil {
+		logrus.Errorf("failed when get reverted data: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	logrus.Info(http.StatusOK, " Success Revert data")
+	ctx.JSON(http.StatusOK, request.MahasiswaResponse{
+		Status:  http.StatusOK,
+		Message: "Success Revert to version " + strconv.Itoa(version),
+		Data:    *after,
+	})
+}
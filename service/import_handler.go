@@ -0,0 +1,301 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// batas jumlah baris dalam satu kali import
+const maxImportRows = 5000
+
+// batas ukuran file import dalam byte, body dibatasi sebelum form di parse
+const maxImportFileSize = 10 << 20
+
+// field yang bisa di import, key mapping mengikuti nama kolom di user_data
+var importFields = []string{"name", "email", "age", "address", "birthdate", "phone_number"}
+
+func (h *Handler) ImportHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportFileSize+multipartOverhead)
+	file, err := ctx.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, respErr.ErrorResponse{
+			Message: fmt.Sprintf("File must not exceed %d bytes", maxImportFileSize),
+			Status:  http.StatusRequestEntityTooLarge,
+		})
+		return
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "No File Upload",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	mode := ctx.DefaultPostForm("mode", request.ImportModeAllOrNothing)
+	if mode != request.ImportModeAllOrNothing && mode != request.ImportModeBestEffort {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "mode must be all_or_nothing or best_effort",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(ctx.DefaultPostForm("dry_run", "false"))
+
+	// mapping: {"name": "Nama Lengkap", "email": "E-mail", ...}
+	mapping := map[string]string{}
+	if raw := ctx.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+				Message: "Invalid mapping: " + err.Error(),
+				Status:  http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	rows, err := readSpreadsheet(file)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	if len(rows) < 2 {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "File must contain a header row and at least one data row",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	if len(rows)-1 > maxImportRows {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: fmt.Sprintf("Too many rows, maximum is %d", maxImportRows),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	columns, err := importColumns(rows[0], mapping)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	response := request.ImportResponse{
+		Status: http.StatusOK,
+		DryRun: dryRun,
+		Mode:   mode,
+		Total:  len(rows) - 1,
+		Rows:   make([]request.ImportRowResult, 0, len(rows)-1),
+	}
+
+	// validasi setiap baris, data yang valid dikumpulkan untuk disimpan
+	var validData []entity.User_data
+	var validIdx []int
+	seen := map[string]int{}
+	for i, row := range rows[1:] {
+		rowNumber := i + 2
+		data, errs := h.validateImportRow(row, columns, userID)
+		if len(errs) == 0 {
+			key := strings.ToLower(data.Name) + "|" + strings.ToLower(data.Email)
+			if first, ok := seen[key]; ok {
+				errs = append(errs, fmt.Sprintf("duplicate of row %d in this file", first))
+			} else {
+				seen[key] = rowNumber
+			}
+		}
+
+		result := request.ImportRowResult{Row: rowNumber, Status: "valid", Errors: errs}
+		if len(errs) > 0 {
+			result.Status = "invalid"
+			response.Invalid++
+		} else {
+			response.Valid++
+			validData = append(validData, *data)
+			validIdx = append(validIdx, len(response.Rows))
+		}
+		response.Rows = append(response.Rows, result)
+	}
+
+	if dryRun {
+		response.Message = "Dry run, no data saved"
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	if mode == request.ImportModeAllOrNothing {
+		if response.Invalid > 0 {
+			var errs []string
+			for _, row := range response.Rows {
+				for _, e := range row.Errors {
+					errs = append(errs, fmt.Sprintf("row %d: %s", row.Row, e))
+				}
+			}
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, respErr.ErrorResponse{
+				Message: fmt.Sprintf("Import cancelled, %d rows are invalid: %s", response.Invalid, strings.Join(errs, "; ")),
+				Status:  http.StatusUnprocessableEntity,
+			})
+			return
+		}
+
+		if err := h.MahasiswaRepository.CreateBatch(validData, auditActor(ctx)); err != nil {
+			logrus.Errorf("failed when importing data: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+				Message: "Import failed, no data saved",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+		for n, idx := range validIdx {
+			response.Rows[idx].Status = "created"
+			response.Rows[idx].ID = validData[n].ID
+			response.Created++
+		}
+	} else {
+		// best effort: simpan satu per satu, baris yang gagal dicatat di report
+		for n, idx := range validIdx {
+			created, err := h.MahasiswaRepository.Create(&validData[n], auditActor(ctx))
+			if err != nil {
+				logrus.Errorf("failed when importing row %d: %v", response.Rows[idx].Row, err)
+				response.Rows[idx].Status = "failed"
+				response.Rows[idx].Errors = append(response.Rows[idx].Errors, "failed to save data")
+				continue
+			}
+			response.Rows[idx].Status = "created"
+			response.Rows[idx].ID = created.ID
+			response.Created++
+		}
+	}
+
+	response.Message = fmt.Sprintf("%d of %d rows imported", response.Created, response.Total)
+	ctx.JSON(http.StatusOK, response)
+}
+
+// importColumns mencari index kolom untuk setiap field berdasarkan header dan mapping
+func importColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	for field := range mapping {
+		if !containsString(importFields, field) {
+			return nil, fmt.Errorf("unknown field %q in mapping", field)
+		}
+	}
+
+	columns := map[string]int{}
+	for _, field := range importFields {
+		name, ok := mapping[field]
+		if !ok {
+			name = field
+		}
+		for i, col := range header {
+			if normalizeHeader(col) == normalizeHeader(name) {
+				columns[field] = i
+				break
+			}
+		}
+		if _, found := columns[field]; !found && ok {
+			return nil, fmt.Errorf("column %q for field %s not found in header", name, field)
+		}
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("column for field name not found in header")
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, fmt.Errorf("column for field email not found in header")
+	}
+	return columns, nil
+}
+
+// validateImportRow memvalidasi satu baris dengan aturan yang sama seperti HandlerCreate
+func (h *Handler) validateImportRow(row []string, columns map[string]int, userID int64) (*entity.User_data, []string) {
+	value := func(field string) string {
+		idx, ok := columns[field]
+		if !ok || idx >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[idx])
+	}
+
+	var errs []string
+	data := &entity.User_data{
+		UserID:  userID,
+		Name:    value("name"),
+		Email:   value("email"),
+		Address: value("address"),
+	}
+
+	if data.Name == "" {
+		errs = append(errs, "name is required")
+	}
+	if data.Email == "" {
+		errs = append(errs, "email is required")
+	} else if !IsValidEmail(data.Email) {
+		errs = append(errs, "invalid email format")
+	}
+	age := 0
+	if raw := value("age"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			errs = append(errs, "age must be a positive number")
+		} else {
+			age = n
+		}
+	}
+	if prof, err := normalizeProfile(value("birthdate"), age, value("phone_number")); err != nil {
+		errs = append(errs, err.Error())
+	} else {
+		data.Birthdate = prof.Birthdate
+		data.Age = prof.Age
+		data.PhoneNumber = prof.PhoneNumber
+	}
+	if len(data.Address) > 100 {
+		errs = append(errs, "address is longer than 100 characters")
+	}
+
+	if data.Name != "" && data.Email != "" {
+		existing, err := h.MahasiswaRepository.GetMahasiswaByNameAndEmail(data.Name, data.Email)
+		if err != nil {
+			logrus.Errorf("failed when checking duplicate: %v", err)
+			errs = append(errs, "failed to check duplicate data")
+		} else if existing != nil {
+			errs = append(errs, "data with the same name and email already exists")
+		}
+	}
+
+	return data, errs
+}
+
+func normalizeHeader(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, "_", "")
+	s = strings.ReplaceAll(s, " ", "")
+	return s
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/pagination"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxOffsetPage halaman terdalam untuk paginasi page/per_page yang sudah deprecated
+const maxOffsetPage = 50
+
+// nullBirthdate pengganti birthdate NULL di ekspresi sort dan di cursor
+const nullBirthdate = "1000-01-01"
+
+// ekspresi sort untuk keyset, kolom yang bisa NULL dibungkus COALESCE supaya perbandingan cursor tetap benar
+var mahasiswaSortKeys = map[string]string{
+	"id":        "id",
+	"name":      "name",
+	"email":     "email",
+	"age":       "COALESCE(" + entity.AgeSQL + ", 0)",
+	"address":   "COALESCE(address, '')",
+	"birthdate": "COALESCE(birthdate, '" + nullBirthdate + "')",
+}
+
+// keysetParams membaca limit dan cursor dari query string
+func keysetParams(ctx *gin.Context, keys []pagination.Key) (pagination.Params, bool) {
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	params, err := pagination.NewParams(keys, limit, ctx.Query("cursor"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return params, false
+	}
+	return params, true
+}
+
+// setLinkHeader menambahkan header Link (RFC 8288) untuk halaman berikutnya dan sebelumnya
+func setLinkHeader(ctx *gin.Context, page pagination.Page) {
+	var links []string
+	for _, link := range []struct{ rel, cursor string }{{"next", page.Next}, {"prev", page.Prev}} {
+		if link.cursor == "" {
+			continue
+		}
+		u := *ctx.Request.URL
+		q := u.Query()
+		q.Set("cursor", link.cursor)
+		q.Set("limit", strconv.Itoa(page.Limit))
+		q.Del("page")
+		u.RawQuery = q.Encode()
+		links = append(links, "<"+u.RequestURI()+`>; rel="`+link.rel+`"`)
+	}
+	if len(links) > 0 {
+		ctx.Header("Link", strings.Join(links, ", "))
+	}
+}
+
+func mahasiswaKeys(sort []request.SortField) []pagination.Key {
+	keys := make([]pagination.Key, 0, len(sort))
+	for _, field := range sort {
+		keys = append(keys, pagination.Key{Expr: mahasiswaSortKeys[field.Column], Desc: field.Desc})
+	}
+	return keys
+}
+
+// mahasiswaCursorValues nilai kolom sort dari satu data, harus sama dengan ekspresi di mahasiswaSortKeys
+func mahasiswaCursorValues(sort []request.SortField) func(entity.User_data) []interface{} {
+	return func(data entity.User_data) []interface{} {
+		values := make([]interface{}, 0, len(sort)+1)
+		for _, field := range sort {
+			switch field.Column {
+			case "id":
+				values = append(values, data.ID)
+			case "name":
+				values = append(values, data.Name)
+			case "email":
+				values = append(values, data.Email)
+			case "age":
+				values = append(values, data.Age)
+			case "address":
+				values = append(values, data.Address)
+			case "birthdate":
+				if data.Birthdate.IsZero() {
+					values = append(values, nullBirthdate)
+				} else {
+					values = append(values, data.Birthdate.String())
+				}
+			}
+		}
+		return append(values, data.ID)
+	}
+}
+
+// kolom users yang boleh dipakai untuk sort
+var userSortColumns = []string{"id", "username", "email"}
+
+func userKeys(sort []request.SortField) []pagination.Key {
+	keys := make([]pagination.Key, 0, len(sort))
+	for _, field := range sort {
+		keys = append(keys, pagination.Key{Expr: field.Column, Desc: field.Desc})
+	}
+	return keys
+}
+
+func userCursorValues(sort []request.SortField) func(entity.User) []interface{} {
+	return func(user entity.User) []interface{} {
+		values := make([]interface{}, 0, len(sort)+1)
+		for _, field := range sort {
+			switch field.Column {
+			case "id":
+				values = append(values, user.ID)
+			case "username":
+				values = append(values, user.Username)
+			case "email":
+				values = append(values, user.Email)
+			}
+		}
+		return append(values, user.ID)
+	}
+}
+
+// abortQueryError mengirim response 400 untuk parameter query (sort, fields, include) yang tidak valid
+func abortQueryError(ctx *gin.Context, err error) {
+	ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+		Message: err.Error(),
+		Status:  http.StatusBadRequest,
+	})
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"ginDatabaseMhs/imaging"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/repository"
+	"ginDatabaseMhs/storage"
+	"ginDatabaseMhs/upload"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AttachmentPresignUpload membuat presigned PUT URL supaya browser upload langsung ke storage.
+// Setelah upload selesai client wajib memanggil AttachmentPresignComplete dengan key yang sama
+func (h *Handler) AttachmentPresignUpload(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.PresignUploadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	// isi file baru bisa diperiksa saat complete, di sini cukup ekstensi dan ukuran yang dijanjikan
+	_, contentType, err := h.UploadPolicy.Check(req.FileName, req.Size)
+	if abortUploadError(ctx, err) {
+		return
+	}
+	// upload baru selalu masuk ke driver default
+	presigner, ok := h.presigner(ctx, h.Storage.Default)
+	if !ok {
+		return
+	}
+	mhs, ok := h.findRecord(ctx, req.RecordID, userID)
+	if !ok {
+		return
+	}
+	if !h.canEdit(ctx, mhs, userID) {
+		return
+	}
+
+	pending := &entity.PendingUpload{
+		Key:         uuid.NewString() + strings.ToLower(filepath.Ext(req.FileName)),
+		RecordID:    req.RecordID,
+		UserID:      userID,
+		FileName:    filepath.Base(req.FileName),
+		ContentType: contentType,
+		Size:        req.Size,
+		ExpiresAt:   time.Now().Add(h.PresignExpiry),
+	}
+	url, err := presigner.PresignPut(ctx, pending.Key, pending.ContentType, pending.Size, h.PresignExpiry)
+	if err != nil {
+		logrus.Errorf("failed when presigning upload: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if err := h.MahasiswaRepository.CreatePendingUpload(pending); err != nil {
+		logrus.Errorf("failed when creating pending upload: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, request.SuccessMessage{
+		Status:  http.StatusCreated,
+		Message: "Upload URL Created",
+		Data: request.PresignedURL{
+			Method:    http.MethodPut,
+			URL:       url,
+			Key:       pending.Key,
+			Headers:   map[string]string{"Content-Type": pending.ContentType},
+			ExpiresAt: pending.ExpiresAt,
+		},
+	})
+}
+
+// AttachmentPresignComplete memastikan file sudah ada di storage dengan ukuran dan content type
+// yang dijanjikan, baru setelah itu lampiran dicatat. File yang tidak sesuai langsung dihapus
+func (h *Handler) AttachmentPresignComplete(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.PresignCompleteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	pending, err := h.MahasiswaRepository.GetPendingUpload(req.Key, userID)
+	if err != nil {
+		logrus.Errorf("failed when get pending upload: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if pending == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Upload not found or already completed",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	// upload yang selesai tepat sebelum URL kadaluarsa masih diterima selama uploadSweepGrace, sama seperti sweeper
+	expired := time.Now().After(pending.ExpiresAt)
+	if time.Now().After(pending.ExpiresAt.Add(uploadSweepGrace)) {
+		h.discardUpload(ctx, pending)
+		abortUploadExpired(ctx)
+		return
+	}
+	mhs, ok := h.findRecord(ctx, pending.RecordID, userID)
+	if !ok {
+		return
+	}
+	if !h.canEdit(ctx, mhs, userID) {
+		return
+	}
+
+	info, err := h.Storage.Primary().Stat(ctx, pending.Key)
+	if errors.Is(err, storage.ErrNotFound) && expired {
+		h.discardUpload(ctx, pending)
+		abortUploadExpired(ctx)
+		return
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		// pending tetap disimpan supaya client masih bisa upload ulang sebelum URL kadaluarsa
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "File has not been uploaded yet",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when stat uploaded file: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if info.Size != pending.Size || info.ContentType != pending.ContentType {
+		h.discardUpload(ctx, pending)
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, respErr.ErrorResponse{
+			Message: fmt.Sprintf("Uploaded file does not match, expected %d bytes of %s but got %d bytes of %s",
+				pending.Size, pending.ContentType, info.Size, info.ContentType),
+			Status: http.StatusUnprocessableEntity,
+		})
+		return
+	}
+	processed, ok := h.inspectStoredUpload(ctx, pending)
+	if !ok {
+		return
+	}
+
+	attachment, err := h.MahasiswaRepository.CompletePendingUpload(ctx.Request.Context(), pending, processed, auditActor(ctx))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Upload not found or already completed",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when completing upload: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, request.SuccessMessage{
+		Status:  http.StatusCreated,
+		Message: "Attachment Uploaded",
+		Data:    attachment,
+	})
+}
+
+func abortUploadExpired(ctx *gin.Context) {
+	ctx.AbortWithStatusJSON(http.StatusGone, respErr.ErrorResponse{
+		Message: "Upload URL has expired, request a new one",
+		Status:  http.StatusGone,
+	})
+}
+
+// AttachmentPresignDownload presigned GET URL untuk lampiran, aksesnya sama dengan AttachmentDownload
+func (h *Handler) AttachmentPresignDownload(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	attachment, _, ok := h.findAttachment(ctx, userID)
+	if !ok {
+		return
+	}
+	presigner, ok := h.presigner(ctx, attachment.Storage)
+	if !ok {
+		return
+	}
+
+	key, fileName, ok := attachmentObject(ctx, attachment)
+	if !ok {
+		return
+	}
+
+	disposition := "inline"
+	if download, _ := strconv.ParseBool(ctx.Query("download")); download {
+		disposition = "attachment"
+	}
+	url, err := presigner.PresignGet(ctx, key, fileName, disposition, h.PresignExpiry)
+	if err != nil {
+		logrus.Errorf("failed when presigning download: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data: request.PresignedURL{
+			Method:    http.MethodGet,
+			URL:       url,
+			ExpiresAt: time.Now().Add(h.PresignExpiry),
+		},
+	})
+}
+
+// presigner response 501 kalau driver storage tidak mendukung presigned URL
+func (h *Handler) presigner(ctx *gin.Context, driver string) (storage.Presigner, bool) {
+	store, ok := h.storageDriver(ctx, driver)
+	if !ok {
+		return nil, false
+	}
+	presigner, err := storage.AsPresigner(store)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusNotImplemented, respErr.ErrorResponse{
+			Message: "Presigned URLs require the s3 storage driver",
+			Status:  http.StatusNotImplemented,
+		})
+		return nil, false
+	}
+	return presigner, true
+}
+
+// inspectStoredUpload memeriksa isi file yang di upload langsung ke storage dengan aturan yang sama
+// seperti upload lewat server, file yang ditolak langsung dibuang. Gambar ikut diproses pipeline,
+// hasilnya dikembalikan untuk disimpan menggantikan file mentah, selain gambar hasilnya nil
+func (h *Handler) inspectStoredUpload(ctx *gin.Context, pending *entity.PendingUpload) (*repository.AttachmentFile, bool) {
+	body, _, err := h.Storage.Primary().Get(ctx, pending.Key)
+	if err != nil {
+		logrus.Errorf("failed when reading uploaded file: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	defer body.Close()
+
+	inspected, err := h.UploadPolicy.Inspect(body, pending.FileName, pending.Size)
+	if err == nil && inspected.ContentType != pending.ContentType {
+		err = &upload.Error{
+			Code:    upload.CodeExtensionMismatch,
+			Message: fmt.Sprintf("file content %s does not match the announced %s", inspected.ContentType, pending.ContentType),
+		}
+	}
+	if err != nil {
+		var uploadErr *upload.Error
+		if errors.As(err, &uploadErr) {
+			h.discardUpload(ctx, pending)
+			abortUploadError(ctx, err)
+			return nil, false
+		}
+		logrus.Errorf("failed when inspecting uploaded file: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if !imaging.Supported(inspected.ContentType) {
+		return nil, true
+	}
+
+	file, ok := h.attachmentFile(ctx, pending.FileName, inspected, pending.Size)
+	if !ok {
+		h.discardUpload(ctx, pending)
+		return nil, false
+	}
+	return &file, true
+}
+
+// discardUpload menghapus file dan pending upload yang tidak akan pernah diselesaikan, kegagalan cukup di log
+func (h *Handler) discardUpload(ctx *gin.Context, pending *entity.PendingUpload) {
+	if err := h.Storage.Primary().Delete(ctx, pending.Key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		logrus.Errorf("failed to remove uploaded file %s: %v", pending.Key, err)
+	}
+	if err := h.MahasiswaRepository.DeletePendingUpload(pending.ID); err != nil {
+		logrus.Errorf("failed to remove pending upload %d: %v", pending.ID, err)
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/phone"
+	"time"
+)
+
+// profile birthdate, age dan phone number yang sudah divalidasi dan dinormalisasi
+type profile struct {
+	Birthdate   entity.Date
+	Age         int
+	PhoneNumber string
+}
+
+// normalizeProfile memvalidasi birthdate (ISO-8601), menurunkan age dari birthdate dan
+// mengubah phone number ke E.164. age yang diisi manual harus sama dengan umur dari birthdate
+func normalizeProfile(birthdate string, age int, phoneNumber string) (profile, error) {
+	var p profile
+	var err error
+
+	if p.Birthdate, err = entity.ParseDate(birthdate); err != nil {
+		return p, fmt.Errorf("birthdate: %v", err)
+	}
+	if age < 0 {
+		return p, fmt.Errorf("age must be a positive number")
+	}
+	p.Age = age
+	if !p.Birthdate.IsZero() {
+		now := time.Now()
+		if p.Birthdate.After(now) {
+			return p, fmt.Errorf("birthdate cannot be in the future")
+		}
+		p.Age = p.Birthdate.AgeAt(now)
+		if age != 0 && age != p.Age {
+			return p, fmt.Errorf("age %d does not match birthdate %s (age %d)", age, p.Birthdate, p.Age)
+		}
+	}
+
+	if p.PhoneNumber, err = phone.Normalize(phoneNumber); err != nil {
+		return p, err
+	}
+	return p, nil
+}
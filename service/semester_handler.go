@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/pagination"
+	"ginDatabaseMhs/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+func (h *Handler) SemesterList(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	// semester terbaru dulu
+	params, ok := keysetParams(ctx, []pagination.Key{{Expr: "start_date", Desc: true}})
+	if !ok {
+		return
+	}
+
+	semesters, err := h.MahasiswaRepository.GetSemestersByUser(userID, params)
+	if err != nil {
+		logrus.Errorf("failed when get semesters: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	semesters, page := pagination.Paginate(params, semesters, func(semester entity.Semester) []interface{} {
+		return []interface{}{semester.StartDate.String(), semester.ID}
+	})
+	setLinkHeader(ctx, page)
+
+	ctx.JSON(http.StatusOK, request.ListResponse{
+		Status:  http.StatusOK,
+		Message: "Success Get Semesters",
+		Data:    semesters,
+		Paging:  &page,
+	})
+}
+
+func (h *Handler) SemesterCreate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	semester, ok := bindSemester(ctx)
+	if !ok {
+		return
+	}
+	semester.UserID = userID
+
+	if err := h.MahasiswaRepository.CreateSemester(semester); err != nil {
+		logrus.Errorf("failed when creating semester: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, request.SuccessMessage{
+		Status:  http.StatusCreated,
+		Message: "Semester Created",
+		Data:    semester,
+	})
+}
+
+func (h *Handler) SemesterGet(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	semesterID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	semester, ok := h.findSemester(ctx, semesterID, userID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Success Get Semester",
+		Data:    semester,
+	})
+}
+
+func (h *Handler) SemesterUpdate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	semesterID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	req, ok := bindSemester(ctx)
+	if !ok {
+		return
+	}
+	if _, ok := h.findSemester(ctx, semesterID, userID); !ok {
+		return
+	}
+
+	_, err := h.MahasiswaRepository.UpdateSemester(semesterID, userID, map[string]interface{}{
+		"name":       req.Name,
+		"start_date": req.StartDate,
+		"end_date":   req.EndDate,
+	})
+	if err != nil {
+		logrus.Errorf("failed when updating semester: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	semester, ok := h.findSemester(ctx, semesterID, userID)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Semester Updated",
+		Data:    semester,
+	})
+}
+
+func (h *Handler) SemesterDelete(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	semesterID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	rowsAffected, err := h.MahasiswaRepository.DeleteSemester(semesterID, userID)
+	if errors.Is(err, repository.ErrInUse) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "Semester still has enrollments",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when deleting semester: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if rowsAffected == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Semester not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Semester Deleted",
+	})
+}
+
+// bindSemester membaca dan memvalidasi body semester, tanggal selesai tidak boleh sebelum tanggal mulai
+func bindSemester(ctx *gin.Context) (*entity.Semester, bool) {
+	var req request.SemesterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	start, err := entity.ParseDate(req.StartDate)
+	if err == nil && start.IsZero() {
+		err = fmt.Errorf("start_date is required")
+	}
+	var end entity.Date
+	if err == nil {
+		end, err = entity.ParseDate(req.EndDate)
+	}
+	if err == nil && (end.IsZero() || end.Before(start.Time)) {
+		err = fmt.Errorf("end_date must not be before start_date")
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	return &entity.Semester{
+		Name:      strings.TrimSpace(req.Name),
+		StartDate: start,
+		EndDate:   end,
+	}, true
+}
+
+func (h *Handler) findSemester(ctx *gin.Context, semesterID, userID int64) (*entity.Semester, bool) {
+	semester, err := h.MahasiswaRepository.GetSemesterByID(semesterID, userID)
+	if err != nil {
+		logrus.Errorf("failed when get semester: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if semester == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Semester not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+	return semester, true
+}
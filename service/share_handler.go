@@ -0,0 +1,223 @@
+package service
+
+import (
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+func (h *Handler) ShareListHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+	if _, ok := h.ownedRecord(ctx, mhsID, userID); !ok {
+		return
+	}
+
+	shares, err := h.MahasiswaRepository.GetSharesByRecord(mhsID)
+	if err != nil {
+		logrus.Errorf("failed when get shares: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Success Get Shares",
+		Data:    shares,
+	})
+}
+
+func (h *Handler) ShareGrantHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req request.ShareRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	if _, ok := h.ownedRecord(ctx, mhsID, userID); !ok {
+		return
+	}
+
+	grantee, err := h.MahasiswaRepository.GetUserByUsernameOrEmail(req.User, req.User)
+	if err != nil {
+		logrus.Errorf("failed when get user: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if grantee == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "User not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	if grantee.ID == userID {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Cannot share a record with its owner",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	share := &entity.RecordShare{
+		RecordID:   mhsID,
+		UserID:     grantee.ID,
+		Permission: req.Permission,
+		GrantedBy:  userID,
+	}
+	if err := h.MahasiswaRepository.SaveShare(share); err != nil {
+		logrus.Errorf("failed when saving share: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Access granted",
+		Data:    share,
+	})
+}
+
+func (h *Handler) ShareRevokeHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+	granteeID, ok := paramID(ctx, "user_id")
+	if !ok {
+		return
+	}
+	if _, ok := h.ownedRecord(ctx, mhsID, userID); !ok {
+		return
+	}
+
+	rowsAffected, err := h.MahasiswaRepository.DeleteShare(mhsID, granteeID)
+	if err != nil {
+		logrus.Errorf("failed when revoking share: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if rowsAffected == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Share not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Access revoked",
+	})
+}
+
+func (h *Handler) SharedWithMeHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	params, ok := keysetParams(ctx, nil)
+	if !ok {
+		return
+	}
+
+	shares, err := h.MahasiswaRepository.GetSharedWithUser(userID, params)
+	if err != nil {
+		logrus.Errorf("failed when get shared records: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	shares, page := pagination.Paginate(params, shares, func(share entity.RecordShare) []interface{} {
+		return []interface{}{share.ID}
+	})
+	setLinkHeader(ctx, page)
+
+	ctx.JSON(http.StatusOK, request.ListResponse{
+		Status:  http.StatusOK,
+		Message: "Success Get Shared Records",
+		Data:    shares,
+		Paging:  &page,
+	})
+}
+
+// ownedRecord hanya pemilik yang boleh mengatur akses, user yang hanya diberi akses mendapat 403
+func (h *Handler) ownedRecord(ctx *gin.Context, mhsID, userID int64) (*entity.User_data, bool) {
+	mhs, ok := h.findRecord(ctx, mhsID, userID)
+	if !ok {
+		return nil, false
+	}
+	if mhs.UserID != userID {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, respErr.ErrorResponse{
+			Message: "Only the owner can manage access",
+			Status:  http.StatusForbidden,
+		})
+		return nil, false
+	}
+	return mhs, true
+}
+
+// canEdit pemilik dan user dengan permission editor boleh mengubah data, selain itu response 403
+func (h *Handler) canEdit(ctx *gin.Context, mhs *entity.User_data, userID int64) bool {
+	if mhs.UserID == userID {
+		return true
+	}
+
+	share, err := h.MahasiswaRepository.GetShare(mhs.ID, userID)
+	if err != nil {
+		logrus.Errorf("failed when get share: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return false
+	}
+	if share == nil || share.Permission != entity.SharePermissionEditor {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, respErr.ErrorResponse{
+			Message: "You only have view access to this record",
+			Status:  http.StatusForbidden,
+		})
+		return false
+	}
+	return true
+}
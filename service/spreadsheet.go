@@ -0,0 +1,52 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+)
+
+var errUnsupportedFormat = errors.New("unsupported file format, only csv and xlsx are allowed")
+
+// readSpreadsheet membaca file csv atau sheet pertama dari file xlsx menjadi baris-baris string
+func readSpreadsheet(file *multipart.FileHeader) ([][]string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".csv":
+		return readCSV(src)
+	case ".xlsx":
+		return readXLSX(src)
+	default:
+		return nil, errUnsupportedFormat
+	}
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return reader.ReadAll()
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	book, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer book.Close()
+
+	sheets := book.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("xlsx file has no sheet")
+	}
+	return book.GetRows(sheets[0])
+}
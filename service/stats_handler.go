@@ -0,0 +1,158 @@
+package service
+
+import (
+	"fmt"
+	"ginDatabaseMhs/address"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultAgeBucket = 5
+	maxAgeBucket     = 50
+)
+
+// StatsHandler agregat data mahasiswa milik user, parameter filter sama seperti /list-Search
+// ditambah age_bucket (lebar rentang histogram umur). Hasil di cache sesuai STATS_CACHE_TTL
+func (h *Handler) StatsHandler(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	// query string di encode ulang supaya urutan parameter tidak mempengaruhi key cache
+	cacheKey := fmt.Sprintf("%d?%s", userID, ctx.Request.URL.Query().Encode())
+	if stats, ok := h.StatsCache.Get(cacheKey); ok {
+		ctx.Header("X-Cache", "HIT")
+		ctx.JSON(http.StatusOK, stats)
+		return
+	}
+
+	bucket := defaultAgeBucket
+	if raw := ctx.Query("age_bucket"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxAgeBucket {
+			abortQueryError(ctx, fmt.Errorf("age_bucket must be a number between 1 and %d", maxAgeBucket))
+			return
+		}
+		bucket = n
+	}
+	fields, ok := h.customFields(ctx, userID)
+	if !ok {
+		return
+	}
+	filter, err := parseMahasiswaFilter(ctx, customFilterSchema(fields))
+	if err != nil {
+		abortFilterError(ctx, err)
+		return
+	}
+
+	stats := request.StatsResponse{Status: http.StatusOK, GeneratedAt: time.Now()}
+	if stats.Total, err = h.MahasiswaRepository.CountByUser(userID, filter); err != nil {
+		h.abortStatsError(ctx, err)
+		return
+	}
+
+	// umur 0 berarti tanggal lahir dan umur tidak diisi, dijadikan NULL supaya tidak masuk rentang 0-n
+	ages, err := h.MahasiswaRepository.CountGroupedByUser(userID, filter, fmt.Sprintf("FLOOR(NULLIF(%s, 0) / %d) * %d", entity.AgeSQL, bucket, bucket))
+	if err != nil {
+		h.abortStatsError(ctx, err)
+		return
+	}
+	stats.AgeHistogram = ageHistogram(ages, bucket)
+
+	if stats.BirthYears, err = h.MahasiswaRepository.CountGroupedByUser(userID, filter, "YEAR(birthdate)"); err != nil {
+		h.abortStatsError(ctx, err)
+		return
+	}
+	stats.BirthYears = labelUnknown(stats.BirthYears)
+
+	addresses, err := h.MahasiswaRepository.CountGroupedByUser(userID, filter, "address")
+	if err != nil {
+		h.abortStatsError(ctx, err)
+		return
+	}
+	stats.Cities = cityCounts(addresses)
+
+	if stats.CreatedPerMonth, err = h.MahasiswaRepository.CountGroupedByUser(userID, filter, "DATE_FORMAT(created_at, '%Y-%m')"); err != nil {
+		h.abortStatsError(ctx, err)
+		return
+	}
+	stats.CreatedPerMonth = labelUnknown(stats.CreatedPerMonth)
+
+	h.StatsCache.Set(cacheKey, stats)
+	ctx.Header("X-Cache", "MISS")
+	ctx.JSON(http.StatusOK, stats)
+}
+
+func (h *Handler) abortStatsError(ctx *gin.Context, err error) {
+	logrus.Errorf("failed when computing statistics: %v", err)
+	ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+		Message: "Internal Server Error",
+		Status:  http.StatusInternalServerError,
+	})
+}
+
+// ageHistogram key berisi batas bawah rentang, data tanpa umur (key kosong) atau dengan tanggal lahir
+// di masa depan (umur negatif) tidak dihitung
+func ageHistogram(counts []entity.GroupCount, bucket int) []request.AgeBucket {
+	histogram := make([]request.AgeBucket, 0, len(counts))
+	for _, c := range counts {
+		min, err := strconv.Atoi(c.Key)
+		if err != nil || min < 0 {
+			continue
+		}
+		histogram = append(histogram, request.AgeBucket{
+			Label: fmt.Sprintf("%d-%d", min, min+bucket-1),
+			Min:   min,
+			Max:   min + bucket - 1,
+			Count: c.Count,
+		})
+	}
+	sort.Slice(histogram, func(i, j int) bool { return histogram[i].Min < histogram[j].Min })
+	return histogram
+}
+
+// cityCounts menggabungkan jumlah per alamat menjadi jumlah per kota, urut dari yang terbanyak
+func cityCounts(addresses []entity.GroupCount) []entity.GroupCount {
+	totals := map[string]int64{}
+	for _, a := range addresses {
+		totals[address.City(a.Key)] += a.Count
+	}
+
+	cities := make([]entity.GroupCount, 0, len(totals))
+	for city, count := range totals {
+		cities = append(cities, entity.GroupCount{Key: city, Count: count})
+	}
+	sort.Slice(cities, func(i, j int) bool {
+		if cities[i].Count != cities[j].Count {
+			return cities[i].Count > cities[j].Count
+		}
+		return cities[i].Key < cities[j].Key
+	})
+	return cities
+}
+
+// labelUnknown nilai NULL (key kosong) diberi label unknown dan diletakkan paling akhir
+func labelUnknown(counts []entity.GroupCount) []entity.GroupCount {
+	out := make([]entity.GroupCount, 0, len(counts))
+	var unknown *entity.GroupCount
+	for i := range counts {
+		if counts[i].Key == "" {
+			unknown = &entity.GroupCount{Key: address.Unknown, Count: counts[i].Count}
+			continue
+		}
+		out = append(out, counts[i])
+	}
+	if unknown != nil {
+		out = append(out, *unknown)
+	}
+	return out
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"fmt"
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+// TagList tag milik user ditambah tag global
+func (h *Handler) TagList(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	params, ok := keysetParams(ctx, nil)
+	if !ok {
+		return
+	}
+
+	tags, err := h.MahasiswaRepository.GetTagsByUser(userID, params)
+	if err != nil {
+		logrus.Errorf("failed when get tags: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	tags, page := pagination.Paginate(params, tags, func(tag entity.Tag) []interface{} {
+		return []interface{}{tag.ID}
+	})
+	setLinkHeader(ctx, page)
+
+	ctx.JSON(http.StatusOK, request.ListResponse{
+		Status:  http.StatusOK,
+		Message: "Success Get Tags",
+		Data:    tags,
+		Paging:  &page,
+	})
+}
+
+func (h *Handler) TagCreate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	if req.Global && !isAdmin(ctx) {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, respErr.ErrorResponse{
+			Message: "Only admin can create global tags",
+			Status:  http.StatusForbidden,
+		})
+		return
+	}
+
+	tag := &entity.Tag{
+		UserID: &userID,
+		Name:   normalizeTagName(req.Name),
+		Color:  strings.ToLower(req.Color),
+	}
+	if req.Global {
+		tag.UserID = nil
+	}
+	if !h.tagNameAvailable(ctx, tag.UserID, tag.Name, 0) {
+		return
+	}
+	if err := h.MahasiswaRepository.CreateTag(tag); err != nil {
+		logrus.Errorf("failed when creating tag: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, request.SuccessMessage{
+		Status:  http.StatusCreated,
+		Message: "Tag Created",
+		Data:    tag,
+	})
+}
+
+// TagUpdate global atau tidaknya tag tidak bisa diubah lewat endpoint ini
+func (h *Handler) TagUpdate(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	tag, ok := h.managedTag(ctx, userID)
+	if !ok {
+		return
+	}
+
+	name := normalizeTagName(req.Name)
+	if !h.tagNameAvailable(ctx, tag.UserID, name, tag.ID) {
+		return
+	}
+	_, err := h.MahasiswaRepository.UpdateTag(tag.ID, map[string]interface{}{
+		"name":  name,
+		"color": strings.ToLower(req.Color),
+	})
+	if err != nil {
+		logrus.Errorf("failed when updating tag: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	tag.Name = name
+	tag.Color = strings.ToLower(req.Color)
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Tag Updated",
+		Data:    tag,
+	})
+}
+
+func (h *Handler) TagDelete(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	tag, ok := h.managedTag(ctx, userID)
+	if !ok {
+		return
+	}
+
+	if _, err := h.MahasiswaRepository.DeleteTag(tag.ID); err != nil {
+		logrus.Errorf("failed when deleting tag: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: "Tag Deleted",
+	})
+}
+
+// TagRecordsHandler memasang tag ke banyak data sekaligus
+func (h *Handler) TagRecordsHandler(ctx *gin.Context) {
+	h.bulkTag(ctx, "tagging", h.MahasiswaRepository.AddRecordTags)
+}
+
+// UntagRecordsHandler melepas tag dari banyak data sekaligus
+func (h *Handler) UntagRecordsHandler(ctx *gin.Context) {
+	h.bulkTag(ctx, "untagging", h.MahasiswaRepository.RemoveRecordTags)
+}
+
+// bulkTag semua data harus bisa diubah user dan semua tag harus terlihat oleh user,
+// kalau ada yang tidak valid tidak ada perubahan yang disimpan
+func (h *Handler) bulkTag(ctx *gin.Context, action string, apply func(recordIDs, tagIDs []int64) (int64, error)) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.BulkTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	recordIDs := uniqueIDs(req.RecordIDs)
+	tagIDs := uniqueIDs(req.TagIDs)
+
+	editable, err := h.MahasiswaRepository.GetEditableRecordIDs(recordIDs, userID)
+	if err != nil {
+		logrus.Errorf("failed when get editable records: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	tags, err := h.MahasiswaRepository.GetTagsByIDs(tagIDs, userID)
+	if err != nil {
+		logrus.Errorf("failed when get tags: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	found := map[int64]bool{}
+	for _, id := range editable {
+		found[id] = true
+	}
+	var errs []string
+	for _, id := range recordIDs {
+		if !found[id] {
+			errs = append(errs, fmt.Sprintf("record %d not found or not editable", id))
+		}
+	}
+	found = map[int64]bool{}
+	for _, tag := range tags {
+		found[tag.ID] = true
+	}
+	for _, id := range tagIDs {
+		if !found[id] {
+			errs = append(errs, fmt.Sprintf("tag %d not found", id))
+		}
+	}
+	if len(errs) > 0 {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid records or tags: " + strings.Join(errs, "; "),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	affected, err := apply(recordIDs, tagIDs)
+	if err != nil {
+		logrus.Errorf("failed when %s records: %v", action, err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, request.SuccessMessage{
+		Status:  http.StatusOK,
+		Message: fmt.Sprintf("Success %s %d records", action, len(recordIDs)),
+		Data: request.BulkTagResult{
+			Records:  len(recordIDs),
+			Tags:     len(tagIDs),
+			Affected: affected,
+		},
+	})
+}
+
+// managedTag tag yang boleh diubah user: tag miliknya sendiri, atau tag global kalau user admin
+func (h *Handler) managedTag(ctx *gin.Context, userID int64) (*entity.Tag, bool) {
+	tagID, ok := paramID(ctx, "id")
+	if !ok {
+		return nil, false
+	}
+
+	tag, err := h.MahasiswaRepository.GetTagByID(tagID, userID)
+	if err != nil {
+		logrus.Errorf("failed when get tag: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if tag == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Tag not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+	if tag.Global() && !isAdmin(ctx) {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, respErr.ErrorResponse{
+			Message: "Only admin can manage global tags",
+			Status:  http.StatusForbidden,
+		})
+		return nil, false
+	}
+	return tag, true
+}
+
+// tagNameAvailable nama tag unik per pemilik (atau di antara tag global), exceptID untuk tag yang sedang diubah
+func (h *Handler) tagNameAvailable(ctx *gin.Context, ownerID *int64, name string, exceptID int64) bool {
+	existing, err := h.MahasiswaRepository.GetTagByName(ownerID, name)
+	if err != nil {
+		logrus.Errorf("failed when checking tag name: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return false
+	}
+	if existing != nil && existing.ID != exceptID {
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "Tag with the same name already exists",
+			Status:  http.StatusConflict,
+		})
+		return false
+	}
+	return true
+}
+
+// normalizeTagName nama tag disimpan huruf kecil supaya filter tidak peka huruf besar
+func normalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func uniqueIDs(ids []int64) []int64 {
+	seen := map[int64]bool{}
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
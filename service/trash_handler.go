@@ -0,0 +1,118 @@
+package service
+
+import (
+	"ginDatabaseMhs/model/entity"
+	"ginDatabaseMhs/model/request"
+	"ginDatabaseMhs/model/respErr"
+	"ginDatabaseMhs/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// format deleted_at di cursor, presisi milidetik supaya data yang dihapus bersamaan tetap berurutan
+const trashCursorLayout = "2006-01-02 15:04:05.000"
+
+func (h *Handler) TrashList(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	// yang terakhir dihapus tampil paling atas
+	params, ok := keysetParams(ctx, []pagination.Key{{Expr: "deleted_at", Desc: true}})
+	if !ok {
+		return
+	}
+
+	data, err := h.MahasiswaRepository.GetTrashByUser(userID, params)
+	if err != nil {
+		logrus.Errorf("failed when get trash: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	data, page := pagination.Paginate(params, data, func(mhs entity.User_data) []interface{} {
+		return []interface{}{mhs.DeletedAt.Time.Format(trashCursorLayout), mhs.ID}
+	})
+	setLinkHeader(ctx, page)
+
+	ctx.JSON(http.StatusOK, request.ResponseToGetAll{
+		Message: "Success Get Trash",
+		UserId:  userID,
+		Data:    len(data),
+		MHS:     data,
+		Paging:  &page,
+	})
+}
+
+func (h *Handler) TrashRestore(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	restored, err := h.MahasiswaRepository.Restore(mhsID, userID, auditActor(ctx))
+	if err != nil {
+		logrus.Errorf("failed when restoring data: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if restored == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found in trash",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	logrus.Info(http.StatusOK, " Success Restore")
+	ctx.JSON(http.StatusOK, request.DeleteResponse{
+		Status:  http.StatusOK,
+		Message: "Success Restore",
+	})
+}
+
+func (h *Handler) TrashPurge(ctx *gin.Context) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	mhsID, ok := paramID(ctx, "id")
+	if !ok {
+		return
+	}
+
+	purged, err := h.MahasiswaRepository.Purge(mhsID, userID, auditActor(ctx))
+	if err != nil {
+		logrus.Errorf("failed when purging data: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if purged == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found in trash",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	logrus.Info(http.StatusOK, " Success Purge")
+	ctx.JSON(http.StatusOK, request.DeleteResponse{
+		Status:  http.StatusOK,
+		Message: "Success Delete Permanently",
+	})
+}
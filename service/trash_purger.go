@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"ginDatabaseMhs/audit"
+	"ginDatabaseMhs/repository"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// RunTrashPurger menghapus permanen data di trash yang lebih lama dari retention,
+// berjalan setiap interval sampai ctx selesai
+func RunTrashPurger(ctx context.Context, repo repository.MahasiswaRepository, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// purge otomatis tercatat di history dengan actor 0 (system)
+		purged, err := repo.PurgeDeletedBefore(time.Now().Add(-retention), audit.System)
+		if err != nil {
+			logrus.Errorf("failed when purging trash: %v", err)
+		} else if len(purged) > 0 {
+			logrus.Infof("purged %d data from trash", len(purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
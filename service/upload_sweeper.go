@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"ginDatabaseMhs/repository"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// uploadSweepGrace jeda setelah URL kadaluarsa supaya upload yang dimulai tepat sebelum kadaluarsa
+// tidak meninggalkan file yatim di storage dan masih bisa diselesaikan lewat AttachmentPresignComplete
+const uploadSweepGrace = 10 * time.Minute
+
+// RunUploadSweeper menghapus pending upload yang kadaluarsa beserta filenya di storage,
+// berjalan setiap interval sampai ctx selesai
+func RunUploadSweeper(ctx context.Context, repo repository.MahasiswaRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := repo.PurgeExpiredUploads(time.Now().Add(-uploadSweepGrace))
+		if err != nil {
+			logrus.Errorf("failed when sweeping expired uploads: %v", err)
+		} else if len(purged) > 0 {
+			logrus.Infof("removed %d expired uploads", len(purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
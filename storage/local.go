@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local menyimpan file di folder Root
+type Local struct {
+	Root string
+}
+
+func NewLocal(root string) (*Local, error) {
+	if root == "" {
+		root = "uploads"
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	return &Local{Root: root}, nil
+}
+
+// Put file ditulis ke file sementara dulu lalu di rename supaya tidak ada file setengah jadi
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (ObjectInfo, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return ObjectInfo{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return ObjectInfo{}, err
+	}
+	return l.Stat(ctx, key)
+}
+
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	info, err := l.Stat(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	name, _ := l.path(key)
+	f, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ObjectInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	return f, info, nil
+}
+
+func (l *Local) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, ObjectInfo, error) {
+	rc, info, err := l.Get(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	f := rc.(*os.File)
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, ObjectInfo{}, err
+	}
+	return readCloser{io.LimitReader(f, length), f}, info, nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Stat content type ditebak dari ekstensi karena disk tidak menyimpan metadata,
+// ETag dibentuk dari waktu ubah dan ukuran file
+func (l *Local) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	fi, err := os.Stat(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return ObjectInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if fi.IsDir() {
+		return ObjectInfo{}, ErrNotFound
+	}
+	return localInfo(key, fi), nil
+}
+
+func (l *Local) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(l.Root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.Root, name)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, localInfo(key, fi))
+		return ctx.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+func (l *Local) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(l.Root, filepath.FromSlash(key)), nil
+}
+
+func localInfo(key string, fi fs.FileInfo) ObjectInfo {
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return ObjectInfo{
+		Key:         key,
+		Size:        fi.Size(),
+		ContentType: contentType,
+		ETag:        fmt.Sprintf(`"%x-%x"`, fi.ModTime().UnixNano(), fi.Size()),
+		ModTime:     fi.ModTime(),
+	}
+}
+
+// readCloser reader yang dibatasi tapi tetap menutup file aslinya
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory menyimpan file di memory proses, dipakai untuk development dan testing
+type Memory struct {
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	data []byte
+	info ObjectInfo
+}
+
+func NewMemory() *Memory {
+	return &Memory{objects: map[string]memoryObject{}}
+}
+
+func (m *Memory) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (ObjectInfo, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	info := ObjectInfo{
+		Key:         key,
+		Size:        int64(len(data)),
+		ContentType: contentType,
+		ETag:        fmt.Sprintf(`"%x"`, md5.Sum(data)),
+		ModTime:     time.Now(),
+	}
+	m.mu.Lock()
+	m.objects[key] = memoryObject{data: data, info: info}
+	m.mu.Unlock()
+	return info, nil
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	m.mu.RLock()
+	obj, ok := m.objects[key]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, ObjectInfo{}, ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(obj.data)), obj.info, nil
+}
+
+func (m *Memory) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, ObjectInfo, error) {
+	m.mu.RLock()
+	obj, ok := m.objects[key]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, ObjectInfo{}, ErrNotFound
+	}
+	if offset > int64(len(obj.data)) {
+		offset = int64(len(obj.data))
+	}
+	end := offset + length
+	if end > int64(len(obj.data)) {
+		end = int64(len(obj.data))
+	}
+	return io.NopCloser(bytes.NewReader(obj.data[offset:end])), obj.info, nil
+}
+
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.objects[key]; !ok {
+		return ErrNotFound
+	}
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *Memory) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	obj, ok := m.objects[key]
+	if !ok {
+		return ObjectInfo{}, ErrNotFound
+	}
+	return obj.info, nil
+}
+
+func (m *Memory) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var objects []ObjectInfo
+	for key, obj := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, obj.info)
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrPresignUnsupported driver tidak bisa membuat URL langsung ke storage
+var ErrPresignUnsupported = errors.New("storage: presigned URLs are not supported by this driver")
+
+// Presigner storage yang bisa membuat URL sementara supaya client upload / download langsung
+// ke storage tanpa lewat server. Saat ini hanya driver s3
+type Presigner interface {
+	// PresignPut URL PUT yang hanya menerima content type dan ukuran yang sama persis
+	PresignPut(ctx context.Context, key, contentType string, size int64, expires time.Duration) (string, error)
+	// PresignGet URL GET, fileName dipakai untuk header Content-Disposition
+	PresignGet(ctx context.Context, key, fileName, disposition string, expires time.Duration) (string, error)
+}
+
+// AsPresigner mengembalikan ErrPresignUnsupported kalau driver tidak mendukung presigned URL
+func AsPresigner(s Storage) (Presigner, error) {
+	p, ok := s.(Presigner)
+	if !ok {
+		return nil, ErrPresignUnsupported
+	}
+	return p, nil
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"io"
+	"mime"
+	"time"
+)
+
+// S3 menyimpan file di bucket S3 atau layanan yang kompatibel (MinIO) lewat Endpoint dan path-style.
+// Kredensial dibaca dari environment / profile AWS seperti biasa
+type S3 struct {
+	Client *s3.Client
+	Bucket string
+}
+
+func NewS3(ctx context.Context, c Config) (*S3, error) {
+	if c.S3Bucket == "" {
+		return nil, errors.New("storage: S3 bucket name is required")
+	}
+	region := c.S3Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	awsConfig, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, err
+	}
+
+	client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		if c.S3Endpoint != "" {
+			o.BaseEndpoint = aws.String(c.S3Endpoint)
+		}
+		o.UsePathStyle = c.S3PathStyle
+	})
+	return &S3{Client: client, Bucket: c.S3Bucket}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (ObjectInfo, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+		Body:   r,
+	}
+	if size >= 0 {
+		input.ContentLength = size
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if _, err := s.Client.PutObject(ctx, input); err != nil {
+		return ObjectInfo{}, err
+	}
+	return s.Stat(ctx, key)
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	out, err := s.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, ObjectInfo{}, s3Error(err)
+	}
+	return out.Body, ObjectInfo{
+		Key:         key,
+		Size:        out.ContentLength,
+		ContentType: aws.ToString(out.ContentType),
+		ETag:        aws.ToString(out.ETag),
+		ModTime:     aws.ToTime(out.LastModified),
+	}, nil
+}
+
+// GetRange memakai header Range bawaan S3 supaya hanya bagian yang diminta yang diunduh
+func (s *S3) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, ObjectInfo, error) {
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	out, err := s.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		return nil, ObjectInfo{}, s3Error(err)
+	}
+	return out.Body, info, nil
+}
+
+// Delete S3 tidak mengembalikan error untuk key yang tidak ada, jadi dicek dulu dengan Stat
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if _, err := s.Stat(ctx, key); err != nil {
+		return err
+	}
+	_, err := s.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (s *S3) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	out, err := s.Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return ObjectInfo{}, s3Error(err)
+	}
+	return ObjectInfo{
+		Key:         key,
+		Size:        out.ContentLength,
+		ContentType: aws.ToString(out.ContentType),
+		ETag:        aws.ToString(out.ETag),
+		ModTime:     aws.ToTime(out.LastModified),
+	}, nil
+}
+
+func (s *S3) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	paginator := s3.NewListObjectsV2Paginator(s.Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.Bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:     aws.ToString(obj.Key),
+				Size:    obj.Size,
+				ETag:    aws.ToString(obj.ETag),
+				ModTime: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+	return objects, nil
+}
+
+func (s *S3) PresignPut(ctx context.Context, key, contentType string, size int64, expires time.Duration) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	req, err := s3.NewPresignClient(s.Client).PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.Bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: size,
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func (s *S3) PresignGet(ctx context.Context, key, fileName, disposition string, expires time.Duration) (string, error) {
+	req, err := s3.NewPresignClient(s.Client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:                     aws.String(s.Bucket),
+		Key:                        aws.String(key),
+		ResponseContentDisposition: aws.String(mime.FormatMediaType(disposition, map[string]string{"filename": fileName})),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func s3Error(err error) error {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	if errors.As(err, &noSuchKey) || errors.As(err, &notFound) {
+		return ErrNotFound
+	}
+	return err
+}
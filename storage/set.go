@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+// Set semua driver yang bisa dipakai lampiran. File baru selalu ditulis ke driver Default, sedangkan
+// file lama dibaca dan dihapus lewat driver yang tercatat di lampiran karena STORAGE_DRIVER bisa berubah
+type Set struct {
+	Default string
+	drivers map[string]Storage
+}
+
+// NewSet membuat driver sesuai konfigurasi sebagai default. Untuk driver local dan s3, driver yang lain
+// ikut dibuat supaya lampiran yang tersimpan sebelum STORAGE_DRIVER diganti tetap bisa dibaca
+func NewSet(ctx context.Context, c Config) (*Set, error) {
+	if c.Driver == "" {
+		c.Driver = DriverLocal
+	}
+	primary, err := New(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	set := NewSingleSet(c.Driver, primary)
+
+	switch c.Driver {
+	case DriverLocal:
+		if c.S3Bucket != "" {
+			s3, err := NewS3(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			set.Register(DriverS3, s3)
+		}
+	case DriverS3:
+		local, err := NewLocal(c.Path)
+		if err != nil {
+			return nil, err
+		}
+		set.Register(DriverLocal, local)
+	}
+	return set, nil
+}
+
+// NewSingleSet set dengan satu driver saja
+func NewSingleSet(driver string, s Storage) *Set {
+	return &Set{Default: driver, drivers: map[string]Storage{driver: s}}
+}
+
+func (s *Set) Register(driver string, store Storage) {
+	s.drivers[driver] = store
+}
+
+// Primary driver default untuk menulis file baru
+func (s *Set) Primary() Storage {
+	return s.drivers[s.Default]
+}
+
+// For driver yang tercatat di lampiran, driver kosong berarti driver default
+func (s *Set) For(driver string) (Storage, error) {
+	if driver == "" {
+		driver = s.Default
+	}
+	store, ok := s.drivers[driver]
+	if !ok {
+		return nil, fmt.Errorf("storage: driver %q is not configured", driver)
+	}
+	return store, nil
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+)
+
+const (
+	DriverLocal  = "local"
+	DriverS3     = "s3"
+	DriverMemory = "memory"
+)
+
+var ErrNotFound = errors.New("storage: object not found")
+
+// ObjectInfo metadata file yang tersimpan, ETag sudah dalam tanda kutip sesuai format header HTTP
+type ObjectInfo struct {
+	Key         string    `json:"key"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	ETag        string    `json:"etag"`
+	ModTime     time.Time `json:"mod_time"`
+}
+
+// Storage tempat menyimpan file lampiran, key memakai pemisah / di semua driver
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (ObjectInfo, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	// GetRange membaca length byte mulai dari offset, ObjectInfo tetap berisi ukuran file utuh
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// Config konfigurasi driver, Path untuk local dan field S3 untuk s3
+type Config struct {
+	Driver string
+	Path   string
+
+	S3Bucket    string
+	S3Region    string
+	S3Endpoint  string
+	S3PathStyle bool
+}
+
+// New membuat storage sesuai driver di konfigurasi
+func New(ctx context.Context, c Config) (Storage, error) {
+	switch c.Driver {
+	case DriverLocal, "":
+		return NewLocal(c.Path)
+	case DriverS3:
+		return NewS3(ctx, c)
+	case DriverMemory:
+		return NewMemory(), nil
+	}
+	return nil, fmt.Errorf("unknown storage driver %q, use local, s3 or memory", c.Driver)
+}
+
+// cleanKey menolak key kosong, key absolut dan key yang keluar dari root dengan ..
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if key == "" || cleaned != key {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	return cleaned, nil
+}
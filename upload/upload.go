@@ -0,0 +1,257 @@
+package upload
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	_ "golang.org/x/image/webp"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// kode error yang dikirim ke client supaya penolakan bisa dibedakan tanpa membaca pesan
+const (
+	CodeMissing           = "file_missing"
+	CodeTooLarge          = "file_too_large"
+	CodeUnsupportedType   = "unsupported_type"
+	CodeExtensionMismatch = "extension_mismatch"
+	CodeInvalidImage      = "invalid_image"
+	CodeImageTooLarge     = "image_dimensions_too_large"
+)
+
+const (
+	CategoryImage    = "image"
+	CategoryDocument = "document"
+)
+
+// sniffLen jumlah byte awal yang dibaca untuk menebak tipe file, sama dengan http.DetectContentType
+const sniffLen = 512
+
+// Error penolakan file, Code salah satu konstanta Code*
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func rejectf(code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Category kelompok tipe file dengan batas ukuran sendiri, Types memetakan MIME ke ekstensi yang sah
+type Category struct {
+	Name    string
+	Types   map[string][]string
+	MaxSize int64
+}
+
+// Categories semua kategori yang dikenal, yang benar-benar diterima diatur lewat Policy
+var Categories = map[string]Category{
+	CategoryImage: {
+		Name: CategoryImage,
+		Types: map[string][]string{
+			"image/jpeg": {".jpg", ".jpeg"},
+			"image/png":  {".png"},
+			"image/webp": {".webp"},
+		},
+	},
+	CategoryDocument: {
+		Name: CategoryDocument,
+		Types: map[string][]string{
+			"application/pdf": {".pdf"},
+		},
+	},
+}
+
+// Policy aturan upload. MaxDimension berlaku untuk lebar dan tinggi gambar, MaxPixels untuk lebar x tinggi
+type Policy struct {
+	Categories   []Category
+	MaxDimension int
+	MaxPixels    int64
+}
+
+// NewPolicy membuat policy dari nama kategori dan batas ukuran per kategori (byte)
+func NewPolicy(names []string, maxSizes map[string]int64, maxDimension int, maxPixels int64) (Policy, error) {
+	p := Policy{MaxDimension: maxDimension, MaxPixels: maxPixels}
+	for _, name := range names {
+		category, ok := Categories[strings.TrimSpace(name)]
+		if !ok {
+			return p, fmt.Errorf("unknown upload category %q, use image or document", name)
+		}
+		category.MaxSize = maxSizes[category.Name]
+		if category.MaxSize < 1 {
+			return p, fmt.Errorf("max size for upload category %s must be positive", category.Name)
+		}
+		p.Categories = append(p.Categories, category)
+	}
+	if len(p.Categories) == 0 {
+		return p, errors.New("at least one upload category is required")
+	}
+	return p, nil
+}
+
+// MaxSize batas ukuran terbesar dari semua kategori, dipakai untuk membatasi body request
+func (p Policy) MaxSize() int64 {
+	var max int64
+	for _, c := range p.Categories {
+		if c.MaxSize > max {
+			max = c.MaxSize
+		}
+	}
+	return max
+}
+
+// Extensions semua ekstensi yang diterima, untuk pesan error
+func (p Policy) Extensions() []string {
+	var exts []string
+	for _, c := range p.Categories {
+		for _, e := range c.Types {
+			exts = append(exts, e...)
+		}
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// Check pemeriksaan awal dari nama dan ukuran file saja, sebelum isi file tersedia (misalnya presigned upload).
+// Mengembalikan kategori dan content type sesuai ekstensi
+func (p Policy) Check(fileName string, size int64) (Category, string, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, c := range p.Categories {
+		for contentType, exts := range c.Types {
+			if containsExt(exts, ext) {
+				if size > c.MaxSize {
+					return c, "", rejectf(CodeTooLarge, "%s files must not exceed %d bytes", c.Name, c.MaxSize)
+				}
+				return c, contentType, nil
+			}
+		}
+	}
+	return Category{}, "", rejectf(CodeUnsupportedType, "file extension %q is not allowed, use %s",
+		ext, strings.Join(p.Extensions(), ", "))
+}
+
+// File hasil Inspect. Reader berisi seluruh isi file (termasuk byte yang sudah dibaca untuk pemeriksaan)
+// dan mengembalikan *Error CodeTooLarge begitu melewati batas ukuran kategori
+type File struct {
+	Category    string
+	ContentType string
+	Width       int
+	Height      int
+	Reader      io.Reader
+}
+
+// Inspect menentukan tipe file dari magic bytes, bukan dari ekstensi atau header client.
+// Ekstensi harus cocok dengan tipe hasil sniffing dan gambar harus bisa dibaca ukurannya.
+// size boleh -1 kalau tidak diketahui, batas ukuran tetap ditegakkan saat Reader dibaca
+func (p Policy) Inspect(r io.Reader, fileName string, size int64) (*File, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+	if n == 0 {
+		return nil, rejectf(CodeMissing, "file is empty")
+	}
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	category, exts, ok := p.category(contentType)
+	if !ok {
+		return nil, rejectf(CodeUnsupportedType, "file content %s is not allowed", contentType)
+	}
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if !containsExt(exts, ext) {
+		return nil, rejectf(CodeExtensionMismatch, "file extension %q does not match its content %s, use %s",
+			ext, contentType, strings.Join(exts, " or "))
+	}
+	if size > category.MaxSize {
+		return nil, rejectf(CodeTooLarge, "%s files must not exceed %d bytes", category.Name, category.MaxSize)
+	}
+
+	file := &File{Category: category.Name, ContentType: contentType}
+	consumed := head
+	if category.Name == CategoryImage {
+		// byte yang dibaca DecodeConfig disimpan supaya bisa disambung lagi ke Reader
+		var extra bytes.Buffer
+		config, _, err := image.DecodeConfig(io.MultiReader(bytes.NewReader(head), io.TeeReader(r, &extra)))
+		if err != nil {
+			return nil, rejectf(CodeInvalidImage, "image could not be read: %v", err)
+		}
+		if err := p.checkDimensions(config.Width, config.Height); err != nil {
+			return nil, err
+		}
+		file.Width, file.Height = config.Width, config.Height
+		consumed = append(consumed, extra.Bytes()...)
+	}
+
+	file.Reader = &limitedReader{
+		r:        io.MultiReader(bytes.NewReader(consumed), r),
+		remain:   category.MaxSize,
+		category: category,
+	}
+	return file, nil
+}
+
+func (p Policy) checkDimensions(width, height int) error {
+	if p.MaxDimension > 0 && (width > p.MaxDimension || height > p.MaxDimension) {
+		return rejectf(CodeImageTooLarge, "image is %dx%d, width and height must not exceed %d pixels",
+			width, height, p.MaxDimension)
+	}
+	if p.MaxPixels > 0 && int64(width)*int64(height) > p.MaxPixels {
+		return rejectf(CodeImageTooLarge, "image is %dx%d, it must not exceed %d pixels in total",
+			width, height, p.MaxPixels)
+	}
+	return nil
+}
+
+func (p Policy) category(contentType string) (Category, []string, bool) {
+	for _, c := range p.Categories {
+		if exts, ok := c.Types[contentType]; ok {
+			return c, exts, true
+		}
+	}
+	return Category{}, nil, false
+}
+
+func containsExt(exts []string, ext string) bool {
+	for _, e := range exts {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// limitedReader seperti io.LimitReader tapi mengembalikan error kalau file lebih besar dari batas,
+// bukan diam-diam memotong file
+type limitedReader struct {
+	r        io.Reader
+	remain   int64
+	category Category
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remain < 0 {
+		return 0, rejectf(CodeTooLarge, "%s files must not exceed %d bytes", l.category.Name, l.category.MaxSize)
+	}
+	// baca satu byte lebih dari sisa supaya file yang tepat di batas tetap lolos
+	if int64(len(p)) > l.remain+1 {
+		p = p[:l.remain+1]
+	}
+	n, err := l.r.Read(p)
+	l.remain -= int64(n)
+	if l.remain < 0 {
+		return 0, rejectf(CodeTooLarge, "%s files must not exceed %d bytes", l.category.Name, l.category.MaxSize)
+	}
+	return n, err
+}
@@ -0,0 +1,98 @@
+package upload
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+)
+
+func pngBytes(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestPolicyInspect(t *testing.T) {
+	const imageMax = 4096
+	policy, err := NewPolicy([]string{CategoryImage, CategoryDocument},
+		map[string]int64{CategoryImage: imageMax, CategoryDocument: 64}, 50, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	small := pngBytes(t, 20, 10)
+	pdf := []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	tests := []struct {
+		name     string
+		data     []byte
+		fileName string
+		size     int64
+		code     string
+	}{
+		{"gambar kecil", small, "foto.png", int64(len(small)), ""},
+		{"ukuran tidak diketahui", small, "foto.PNG", -1, ""},
+		{"pdf", pdf, "krs.pdf", int64(len(pdf)), ""},
+		{"kosong", nil, "foto.png", 0, CodeMissing},
+		{"melebihi batas kategori", small, "foto.png", imageMax + 1, CodeTooLarge},
+		{"pdf melebihi batas dokumen", pdf, "krs.pdf", 65, CodeTooLarge},
+		{"ekstensi tidak cocok", small, "foto.jpg", int64(len(small)), CodeExtensionMismatch},
+		{"pdf dengan ekstensi gambar", pdf, "krs.png", int64(len(pdf)), CodeExtensionMismatch},
+		{"tipe tidak diizinkan", []byte("nama,email\nbudi,budi@mail.com\n"), "data.csv", 30, CodeUnsupportedType},
+		{"gambar rusak", small[:20], "foto.png", 20, CodeInvalidImage},
+		{"lebar melebihi batas", pngBytes(t, 51, 10), "foto.png", -1, CodeImageTooLarge},
+		{"tinggi melebihi batas", pngBytes(t, 10, 51), "foto.png", -1, CodeImageTooLarge},
+		{"piksel melebihi batas", pngBytes(t, 40, 30), "foto.png", -1, CodeImageTooLarge},
+		{"tepat di batas piksel", pngBytes(t, 40, 25), "foto.png", -1, ""},
+	}
+	for _, tt := range tests {
+		file, err := policy.Inspect(bytes.NewReader(tt.data), tt.fileName, tt.size)
+		if tt.code != "" {
+			var uerr *Error
+			if !errors.As(err, &uerr) || uerr.Code != tt.code {
+				t.Errorf("%s: error %v, want code %s", tt.name, err, tt.code)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		// Reader harus mengembalikan isi file utuh, termasuk byte yang sudah dibaca Inspect
+		got, err := io.ReadAll(file.Reader)
+		if err != nil || !bytes.Equal(got, tt.data) {
+			t.Errorf("%s: reader returned %d bytes (%v), want %d", tt.name, len(got), err, len(tt.data))
+		}
+	}
+}
+
+func TestPolicyInspectReaderLimit(t *testing.T) {
+	data := pngBytes(t, 20, 10)
+	// ukuran dari client tidak diketahui, batas ditegakkan saat Reader dibaca
+	for _, tt := range []struct {
+		maxSize int64
+		tooBig  bool
+	}{
+		{int64(len(data)), false},
+		{int64(len(data)) - 1, true},
+	} {
+		policy, err := NewPolicy([]string{CategoryImage}, map[string]int64{CategoryImage: tt.maxSize}, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file, err := policy.Inspect(bytes.NewReader(data), "foto.png", -1)
+		if err != nil {
+			t.Fatalf("max %d: %v", tt.maxSize, err)
+		}
+		_, err = io.ReadAll(file.Reader)
+		var uerr *Error
+		if tooBig := errors.As(err, &uerr) && uerr.Code == CodeTooLarge; tooBig != tt.tooBig {
+			t.Errorf("max %d: error %v, want too large %v", tt.maxSize, err, tt.tooBig)
+		}
+	}
+}